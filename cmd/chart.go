@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/guptarohit/asciigraph"
+	"github.com/pterm/pterm"
+	"github.com/zmoog/ws/v2/history"
+)
+
+// sparkTicks are the characters of a sparkline, from the lowest to the
+// highest value.
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders the values as a line of block characters scaled
+// between their minimum and maximum. NaN values are rendered as spaces.
+func sparkline(values []float64) string {
+	low, high, ok := valueRange(values)
+	if !ok {
+		return "-"
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			sb.WriteRune(' ')
+		case high == low:
+			sb.WriteRune(sparkTicks[len(sparkTicks)/2])
+		default:
+			i := int(math.Round((v - low) / (high - low) * float64(len(sparkTicks)-1)))
+			sb.WriteRune(sparkTicks[i])
+		}
+	}
+
+	return sb.String()
+}
+
+// temperatureChart renders the air temperature of the samples and the
+// desired temperature as two lines between since and until, with a
+// column per bucket of time, width in total, on height lines. Buckets
+// without samples are left as gaps.
+func temperatureChart(samples []history.Sample, since, until time.Time, width, height int) string {
+	current := history.Series(samples, history.AirTemperature, since, until, width)
+	desired := history.Series(samples, history.SetpointTemperature, since, until, width)
+
+	if _, _, ok := valueRange(append(slices.Clone(current), desired...)); !ok {
+		return "No samples to chart\n"
+	}
+
+	layout := "15:04"
+	if until.Sub(since) > 24*time.Hour {
+		layout = "01-02 15:04"
+	}
+
+	opts := []asciigraph.Option{
+		// The plot has a line more than its height, for the lowest value.
+		asciigraph.Height(height - 1),
+		asciigraph.Precision(1),
+		asciigraph.XAxisRange(float64(since.Unix()), float64(until.Unix())),
+		asciigraph.XAxisValueFormatter(func(v float64) string {
+			return time.Unix(int64(v), 0).Local().Format(layout)
+		}),
+	}
+	if pterm.PrintColor {
+		opts = append(opts, asciigraph.SeriesColors(asciigraph.Cyan, asciigraph.Yellow))
+	}
+
+	legend := fmt.Sprintf("      %s Temperature (current)   %s Temperature (desired)",
+		pterm.FgLightCyan.Sprint("■"),
+		pterm.FgLightYellow.Sprint("■"),
+	)
+
+	return asciigraph.PlotMany([][]float64{current, desired}, opts...) + "\n\n" + legend + "\n"
+}
+
+// formatChartValue formats a value of a series, or "-" for a bucket
+// without samples.
+func formatChartValue(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.1f", v)
+}
+
+// valueRange returns the minimum and maximum of the values, ignoring
+// NaN. It returns false if there are no values.
+func valueRange(values []float64) (low, high float64, ok bool) {
+	low, high = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		low, high = math.Min(low, v), math.Max(high, v)
+		ok = true
+	}
+
+	return low, high, ok
+}
+
+// formatSpan formats a duration without the zero minutes and seconds,
+// e.g. "6h" instead of "6h0m0s".
+func formatSpan(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
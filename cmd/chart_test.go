@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/zmoog/ws/v2/history"
+)
+
+func TestTemperatureChart(t *testing.T) {
+	// Arrange
+	pterm.DisableColor()
+	defer pterm.EnableColor()
+
+	since := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	until := since.Add(4 * time.Hour)
+
+	var samples []history.Sample
+	for i := 0; i < 4; i++ {
+		samples = append(samples, history.Sample{
+			Time:                since.Add(time.Duration(i) * time.Hour),
+			AirTemperature:      18 + float64(i),
+			SetpointTemperature: 21,
+		})
+	}
+
+	// Act
+	chart := temperatureChart(samples, since, until, 8, 6)
+
+	// Assert
+	lines := strings.Split(strings.TrimRight(chart, "\n"), "\n")
+
+	var rows []string
+	for _, line := range lines {
+		if strings.ContainsAny(line, "┤┼") {
+			rows = append(rows, line)
+		}
+	}
+	if len(rows) != 6 {
+		t.Fatalf("Expected 6 lines, got %d:\n%s", len(rows), chart)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(rows[0]), "21.0") || !strings.HasPrefix(strings.TrimSpace(rows[5]), "18.0") {
+		t.Errorf("Expected a scale from 21.0 to 18.0, got:\n%s", chart)
+	}
+	if legend := lines[len(lines)-1]; !strings.Contains(legend, "Temperature (current)") || !strings.Contains(legend, "Temperature (desired)") {
+		t.Errorf("Expected the legend of both series, got %q", legend)
+	}
+}
+
+func TestTemperatureChart_NoSamples(t *testing.T) {
+	// Arrange
+	until := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	// Act
+	chart := temperatureChart(nil, until.Add(-time.Hour), until, 8, 6)
+
+	// Assert
+	if chart != "No samples to chart\n" {
+		t.Errorf("Expected no chart, got %q", chart)
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/spf13/viper"
+	"github.com/zmoog/ws/v2/ws"
+	"github.com/zmoog/ws/v2/ws/identity"
+)
+
+// newIdentityManager creates an identity manager using the
+// credentials from the configuration.
+func newIdentityManager() identity.Manager {
+	config := identity.Config{
+		Username:  viper.GetString("username"),
+		Password:  viper.GetString("password"),
+		WebApiKey: viper.GetString("web_api_key"),
+	}
+
+	if endpoint := viper.GetString("identity_endpoint"); endpoint != "" {
+		config.SignInEndpoint, config.TokenEndpoint = identity.EmulatorEndpoints(endpoint)
+	}
+
+	return identity.NewManager(config)
+}
+
+// newClient creates a Wavin Sentio API client using the
+// endpoint and credentials from the configuration.
+func newClient() *ws.Client {
+	return ws.NewClient(
+		newIdentityManager(),
+		viper.GetString("api_endpoint"),
+		ws.WithMaxRetries(viper.GetInt("retries")),
+	)
+}
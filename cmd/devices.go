@@ -5,13 +5,13 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"github.com/zmoog/ws/v2/feedback"
 	"github.com/zmoog/ws/v2/ws"
-	"github.com/zmoog/ws/v2/ws/identity"
 )
 
 // devicesCmd represents the devices command
@@ -23,30 +23,40 @@ var devicesCmd = &cobra.Command{
 
 // listCmd represents the list command
 var listDevicesCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List devices",
-	Long:  `List the devices in your account.`,
+	Use:         "list",
+	Short:       "List devices",
+	Long:        `List the devices in your account.`,
+	Annotations: map[string]string{annotationTabular: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		identityManager := identity.NewManager(
-			identity.Config{
-				Username:  viper.GetString("username"),
-				Password:  viper.GetString("password"),
-				WebApiKey: viper.GetString("web_api_key"),
-			},
-		)
-		client := ws.NewClient(
-			identityManager,
-			viper.GetString("api_endpoint"),
-		)
-
-		devices, err := client.ListDevices()
+		client := newClient()
+
+		devices, err := client.ListDevicesContext(cmd.Context())
 		if err != nil {
-			return fmt.Errorf("failed to list devices: %v", err)
+			return fmt.Errorf("failed to list devices: %w", err)
 		}
 
-		_ = feedback.PrintResult(deviceResult{Devices: devices})
+		return feedback.PrintResult(deviceResult{Devices: devices})
+	},
+}
+
+// showDeviceCmd represents the show command
+var showDeviceCmd = &cobra.Command{
+	Use:   "show NAME",
+	Short: "Show a device",
+	Long: `Show the details of a device: registration, firmware, system settings,
+and outdoor temperature sensors.
 
-		return nil
+With --output json, the full device is printed, including the rooms.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+
+		device, err := client.GetDeviceContext(cmd.Context(), args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get device: %w", err)
+		}
+
+		return feedback.PrintResult(deviceDetailResult{device: device})
 	},
 }
 
@@ -72,7 +82,7 @@ func (r deviceResult) Table() string {
 			device.SerialNumber,
 			device.FirmwareAvailable,
 			device.FirmwareInstalled,
-			device.Type,
+			device.Type.String(),
 			device.LastHeartbeat.Format("2006-01-02 15:04:05"),
 		})
 	}
@@ -81,6 +91,31 @@ func (r deviceResult) Table() string {
 	return rendered
 }
 
+func (r deviceResult) Rows() ([]string, [][]string) {
+	header := []string{
+		"Name",
+		"Serial Number",
+		"Firmware Available",
+		"Firmware Installed",
+		"Type",
+		"Last Heartbeat",
+	}
+
+	records := make([][]string, 0, len(r.Devices))
+	for _, device := range r.Devices {
+		records = append(records, []string{
+			device.Name,
+			device.SerialNumber,
+			device.FirmwareAvailable,
+			device.FirmwareInstalled,
+			string(device.Type),
+			device.LastHeartbeat.Format(time.RFC3339),
+		})
+	}
+
+	return header, records
+}
+
 func (r deviceResult) String() string {
 	return r.Table()
 }
@@ -89,6 +124,96 @@ func (r deviceResult) Data() any {
 	return r.Devices
 }
 
+type deviceDetailResult struct {
+	device ws.Device
+}
+
+func (r deviceDetailResult) Table() string {
+	device := r.device
+	sentio := device.LastConfig.Sentio
+
+	var sb strings.Builder
+
+	writeSection(&sb, "Device", pterm.TableData{
+		{"Name", device.Name},
+		{"Title", deviceTitle(sentio)},
+		{"Type", device.Type.String()},
+		{"Serial number", device.SerialNumber},
+		{"Registration key", device.RegistrationKey},
+		{"Firmware installed", device.FirmwareInstalled},
+		{"Firmware available", device.FirmwareAvailable},
+		{"Created", formatDeviceTime(device.CreateTime)},
+		{"Updated", formatDeviceTime(device.UpdateTime)},
+		{"Last heartbeat", formatDeviceTime(device.LastHeartbeat)},
+	}, false)
+
+	available := make([]string, 0, len(sentio.AvailableHcModes))
+	for _, mode := range sentio.AvailableHcModes {
+		available = append(available, mode.String())
+	}
+
+	writeSection(&sb, "Configuration", pterm.TableData{
+		{"Name", device.LastConfig.Name},
+		{"Last update", formatDeviceTime(device.LastConfig.Timestamp)},
+		{"Rooms", fmt.Sprintf("%d", len(sentio.Rooms))},
+		{"Heating/cooling mode", sentio.HcMode.String()},
+		{"Available heating/cooling modes", strings.Join(available, ", ")},
+		{"Automatic heating/cooling mode", sentio.AutomaticHcMode.String()},
+		{"Standby mode", sentio.StandbyMode.String()},
+		{"Vacation mode", sentio.VacationSettings.VacationMode.String()},
+		{"Vacation until", formatVacationUntil(sentio.VacationSettings.VacationModeUntil)},
+		{"Quiet mode", sentio.QuietSettings.Mode.String()},
+	}, false)
+
+	sensors := pterm.TableData{{"ID", "Outdoor temperature"}}
+	for _, sensor := range sentio.OutdoorTemperatureSensors {
+		sensors = append(sensors, []string{sensor.ID, fmt.Sprintf("%.1f", sensor.OutdoorTemperature)})
+	}
+	if len(sensors) == 1 {
+		sensors = append(sensors, []string{"-", "-"})
+	}
+	writeSection(&sb, "Outdoor temperature sensors", sensors, true)
+
+	return sb.String()
+}
+
+// writeSection writes a section title followed by a table, using the
+// first row as the table header when hasHeader is set.
+func writeSection(sb *strings.Builder, title string, data pterm.TableData, hasHeader bool) {
+	sb.WriteString(pterm.DefaultSection.Sprint(title))
+
+	table := pterm.DefaultTable.WithHasHeader(hasHeader).WithData(data)
+
+	rendered, err := table.Srender()
+	if err != nil {
+		rendered = fmt.Sprintf("failed to render table: %s", err)
+	}
+	sb.WriteString(rendered)
+	sb.WriteString("\n")
+}
+
+func deviceTitle(sentio ws.Sentio) string {
+	if sentio.TitlePersonalized != "" {
+		return sentio.TitlePersonalized
+	}
+	return sentio.Title
+}
+
+func formatDeviceTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func (r deviceDetailResult) String() string {
+	return r.Table()
+}
+
+func (r deviceDetailResult) Data() any {
+	return r.device
+}
+
 func init() {
 	rootCmd.AddCommand(devicesCmd)
 
@@ -103,4 +228,5 @@ func init() {
 	// devicesCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 
 	devicesCmd.AddCommand(listDevicesCmd)
+	devicesCmd.AddCommand(showDeviceCmd)
 }
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zmoog/ws/v2/ws"
+)
+
+// Exit codes returned by the ws command.
+const (
+	exitCodeError            = 1
+	exitCodeTimeout          = 2
+	exitCodeUnauthenticated  = 3
+	exitCodePermissionDenied = 4
+	exitCodeNotFound         = 5
+	exitCodeUnavailable      = 6
+)
+
+// exitCode returns the process exit code for the error returned
+// by a command.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return exitCodeTimeout
+	case ws.IsUnauthenticated(err):
+		return exitCodeUnauthenticated
+	case ws.IsPermissionDenied(err):
+		return exitCodePermissionDenied
+	case ws.IsNotFound(err):
+		return exitCodeNotFound
+	case ws.IsUnavailable(err):
+		return exitCodeUnavailable
+	default:
+		return exitCodeError
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+	"github.com/zmoog/ws/v2/exporter"
+	"github.com/zmoog/ws/v2/feedback"
+)
+
+var (
+	exporterListen   string
+	exporterInterval time.Duration
+)
+
+// exporterCmd represents the exporter command
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Run a Prometheus exporter",
+	Long: `Run a Prometheus exporter serving the state of the devices and rooms
+in your account on the /metrics endpoint.
+
+The devices are polled at regular intervals, and the ws_up metric reports
+whether the last poll was successful.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if exporterInterval <= 0 {
+			return fmt.Errorf("invalid interval: %s", exporterInterval)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		collector := exporter.NewCollector(newClient())
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(
+			collector,
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintln(w, `<html><body><h1>ws exporter</h1><p><a href="/metrics">Metrics</a></p></body></html>`)
+		})
+
+		server := &http.Server{
+			Addr:              exporterListen,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go collector.Run(ctx, exporterInterval)
+
+		go func() {
+			<-ctx.Done()
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		feedback.Error(fmt.Sprintf("Serving metrics on %s/metrics", exporterListen))
+
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to serve metrics: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exporterCmd)
+
+	exporterCmd.Flags().StringVarP(&exporterListen, "listen", "l", ":9810", "The address to listen on for HTTP requests")
+	exporterCmd.Flags().DurationVarP(&exporterInterval, "interval", "i", time.Minute, "Polling interval")
+}
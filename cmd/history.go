@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/zmoog/ws/v2/feedback"
+	"github.com/zmoog/ws/v2/history"
+)
+
+var (
+	historyDB      string
+	historySince   time.Duration
+	recordInterval time.Duration
+	chartWidth     int
+	chartHeight    int
+)
+
+// recordCmd represents the record command
+var recordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record the room readings to the local history",
+	Long: `Record the readings of the rooms of all the devices in your account to a
+local database, polling the devices at regular intervals.
+
+Each poll appends a sample per room with the air temperature, humidity,
+desired temperature, temperature and dehumidifier states, and outdoor
+temperature. Use the history command to query them.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if recordInterval <= 0 {
+			return fmt.Errorf("invalid interval: %s", recordInterval)
+		}
+
+		path, err := historyPath()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+		recorder := history.NewRecorder(newClient(), history.NewStore(path), logger)
+
+		feedback.Error(fmt.Sprintf("Recording to %s every %s", path, recordInterval))
+
+		recorder.Run(ctx, recordInterval)
+
+		return nil
+	},
+}
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the recorded room readings",
+	Long: `Show the room readings recorded by the record command, oldest first.
+
+Use --room to show a single room, --device-name to show the rooms of a single
+device, and --since to change how far back to look. With --output csv, the
+readings are printed as CSV, handy to import them into a spreadsheet.`,
+	Annotations: map[string]string{annotationTabular: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if historySince <= 0 {
+			return fmt.Errorf("invalid since: %s", historySince)
+		}
+
+		path, err := historyPath()
+		if err != nil {
+			return err
+		}
+
+		samples, err := history.NewStore(path).Query(history.Query{
+			Device: ulc,
+			Room:   room,
+			Since:  time.Now().Add(-historySince),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to query history: %w", err)
+		}
+
+		return feedback.PrintResult(historyResult{samples: samples})
+	},
+}
+
+// historyChartCmd represents the history chart command
+var historyChartCmd = &cobra.Command{
+	Use:   "chart",
+	Short: "Chart the recorded temperature of a room",
+	Long: `Draw a line chart of the recorded air temperature of a room and of the
+desired temperature, to spot the rooms slow to reach the setpoint.
+
+The chart is --width columns wide and --height lines high, and the samples
+are averaged over a column per time interval. The intervals without
+samples are left blank.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if historySince <= 0 {
+			return fmt.Errorf("invalid since: %s", historySince)
+		}
+		if chartWidth < 2 || chartHeight < 2 {
+			return fmt.Errorf("invalid chart size: %dx%d", chartWidth, chartHeight)
+		}
+
+		path, err := historyPath()
+		if err != nil {
+			return err
+		}
+
+		until := time.Now()
+		since := until.Add(-historySince)
+
+		samples, err := history.NewStore(path).Query(history.Query{
+			Device: ulc,
+			Room:   room,
+			Since:  since,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to query history: %w", err)
+		}
+
+		rooms := make(map[string]bool)
+		for _, sample := range samples {
+			rooms[sample.Device+"/"+sample.RoomID] = true
+		}
+		if len(rooms) > 1 {
+			return fmt.Errorf("room %q matches %d rooms, use --device-name to select one", room, len(rooms))
+		}
+
+		return feedback.PrintResult(historyChartResult{
+			samples: samples,
+			since:   since,
+			until:   until,
+		})
+	},
+}
+
+// historyPath returns the path of the history database, from --db or
+// the default one in the configuration directory.
+func historyPath() (string, error) {
+	if historyDB != "" {
+		return historyDB, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the history database: %w", err)
+	}
+
+	return filepath.Join(home, ".ws", "history.db"), nil
+}
+
+type historyResult struct {
+	samples []history.Sample
+}
+
+var historyHeader = []string{
+	"Time",
+	"Device",
+	"Room",
+	"Temperature (current)",
+	"Temperature (desired)",
+	"Humidity (current)",
+	"Temperature state",
+	"Dehumidification state",
+	"Outdoor temperature",
+}
+
+func (r historyResult) Table() string {
+	table := pterm.TableData{historyHeader}
+	for _, sample := range r.samples {
+		table = append(table, []string{
+			sample.Time.Local().Format("2006-01-02 15:04"),
+			sample.Device,
+			sample.Room,
+			fmt.Sprintf("%.1f", sample.AirTemperature),
+			fmt.Sprintf("%.1f", sample.SetpointTemperature),
+			fmt.Sprintf("%.1f", sample.Humidity),
+			sample.TemperatureState.String(),
+			sample.DehumidifierState.String(),
+			formatOutdoorTemperature(sample.OutdoorTemperature),
+		})
+	}
+
+	rendered, err := pterm.DefaultTable.WithHasHeader().WithData(table).Srender()
+	if err != nil {
+		return fmt.Sprintf("failed to render table: %s", err)
+	}
+
+	return rendered + "\n"
+}
+
+func (r historyResult) Rows() ([]string, [][]string) {
+	records := make([][]string, 0, len(r.samples))
+	for _, sample := range r.samples {
+		var outdoor string
+		if sample.OutdoorTemperature != nil {
+			outdoor = formatFloat(*sample.OutdoorTemperature)
+		}
+
+		records = append(records, []string{
+			sample.Time.Format(time.RFC3339),
+			sample.Device,
+			sample.Room,
+			formatFloat(sample.AirTemperature),
+			formatFloat(sample.SetpointTemperature),
+			formatFloat(sample.Humidity),
+			string(sample.TemperatureState),
+			string(sample.DehumidifierState),
+			outdoor,
+		})
+	}
+
+	return historyHeader, records
+}
+
+// formatOutdoorTemperature formats an optional outdoor temperature.
+func formatOutdoorTemperature(temperature *float64) string {
+	if temperature == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.1f", *temperature)
+}
+
+func (r historyResult) String() string {
+	return r.Table()
+}
+
+func (r historyResult) Data() any {
+	if r.samples == nil {
+		return []history.Sample{}
+	}
+
+	return r.samples
+}
+
+// historyChartResult is a line chart of the samples of a room.
+type historyChartResult struct {
+	samples []history.Sample
+	since   time.Time
+	until   time.Time
+}
+
+func (r historyChartResult) Table() string {
+	if len(r.samples) == 0 {
+		return "No samples to chart\n"
+	}
+
+	return pterm.DefaultSection.Sprint(r.samples[0].Room) +
+		temperatureChart(r.samples, r.since, r.until, chartWidth, chartHeight)
+}
+
+func (r historyChartResult) String() string {
+	return r.Table()
+}
+
+func (r historyChartResult) Data() any {
+	return historyResult{samples: r.samples}.Data()
+}
+
+func init() {
+	rootCmd.AddCommand(recordCmd)
+	rootCmd.AddCommand(historyCmd)
+
+	recordCmd.Flags().StringVar(&historyDB, "db", "", "The history database (default is $HOME/.ws/history.db)")
+	recordCmd.Flags().DurationVarP(&recordInterval, "interval", "i", 5*time.Minute, "Polling interval")
+
+	historyCmd.Flags().StringVar(&historyDB, "db", "", "The history database (default is $HOME/.ws/history.db)")
+	historyCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name (default to all the devices)")
+	historyCmd.Flags().StringVarP(&room, "room", "r", "", "Room ID or title (default to all the rooms)")
+	historyCmd.Flags().DurationVar(&historySince, "since", 24*time.Hour, "How far back to look (e.g. 24h)")
+
+	historyCmd.AddCommand(historyChartCmd)
+
+	historyChartCmd.Flags().StringVar(&historyDB, "db", "", "The history database (default is $HOME/.ws/history.db)")
+	historyChartCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name (default to all the devices)")
+	historyChartCmd.Flags().StringVarP(&room, "room", "r", "", "Room ID or title")
+	historyChartCmd.Flags().DurationVar(&historySince, "since", 24*time.Hour, "How far back to look (e.g. 24h)")
+	historyChartCmd.Flags().IntVar(&chartWidth, "width", 72, "The width of the chart, in columns, excluding the axis labels")
+	historyChartCmd.Flags().IntVar(&chartHeight, "height", 12, "The height of the chart, in lines, excluding the time axis and the legend")
+	_ = historyChartCmd.MarkFlagRequired("room")
+}
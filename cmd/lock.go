@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/zmoog/ws/v2/feedback"
+	"github.com/zmoog/ws/v2/ws"
+)
+
+// lockRoomsCmd represents the rooms lock command
+var lockRoomsCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Lock the thermostats of rooms",
+	Long: `Lock the thermostats of one room (--room), all the rooms (--all), or the
+rooms with a title matching a pattern (--match), e.g. "Bedroom*".`,
+	RunE: setLockMode(ws.LockModeLocked),
+}
+
+// unlockRoomsCmd represents the rooms unlock command
+var unlockRoomsCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Unlock the thermostats of rooms",
+	Long: `Unlock the thermostats of one room (--room), all the rooms (--all), or the
+rooms with a title matching a pattern (--match), e.g. "Bedroom*".`,
+	RunE: setLockMode(ws.LockModeUnlocked),
+}
+
+// setLockMode returns a command changing the lock mode of the
+// selected rooms.
+func setLockMode(mode ws.LockMode) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+
+		rooms, err := selectedRooms(cmd, client)
+		if err != nil {
+			return err
+		}
+
+		updated, err := client.SetLockModeContext(cmd.Context(), ulc, mode, rooms...)
+		if err != nil {
+			return fmt.Errorf("failed to set lock mode: %w", err)
+		}
+
+		return feedback.PrintResult(lockResult{rooms: updated})
+	}
+}
+
+type lockResult struct {
+	rooms []ws.Room
+}
+
+func (r lockResult) Table() string {
+	table := pterm.TableData{{"Name", "Lock mode"}}
+	for _, room := range r.rooms {
+		table = append(table, []string{room.Title, room.LockMode.String()})
+	}
+
+	rendered, err := pterm.DefaultTable.WithHasHeader().WithData(table).Srender()
+	if err != nil {
+		return fmt.Sprintf("failed to render table: %s", err)
+	}
+
+	return rendered + "\n"
+}
+
+func (r lockResult) String() string {
+	return r.Table()
+}
+
+func (r lockResult) Data() any {
+	return r.rooms
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{lockRoomsCmd, unlockRoomsCmd} {
+		roomsCmd.AddCommand(cmd)
+
+		cmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+		_ = cmd.MarkFlagRequired("device-name")
+		addRoomSelectorFlags(cmd)
+		cmd.MarkFlagsOneRequired("room", "all", "match")
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zmoog/ws/v2/ws"
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+func TestLockRoomsCmd(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(wstest.NewDevice("devices/home",
+		wstest.NewRoom("room-1", "Kitchen"),
+		wstest.NewRoom("room-2", "Bedroom"),
+		wstest.NewRoom("room-3", "Bedroom 2"),
+	))
+	defer server.Close()
+
+	// Act
+	out, err := runCommand(t, server, "rooms", "lock", "--device-name", "devices/home", "--match", "bedroom*", "--output", "json")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var rooms []ws.Room
+	if err := json.Unmarshal([]byte(out), &rooms); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(rooms) != 2 || rooms[0].ID != "room-2" || rooms[1].ID != "room-3" {
+		t.Fatalf("Expected room-2 and room-3, got %+v", rooms)
+	}
+	for _, room := range rooms {
+		if room.LockMode != ws.LockModeLocked {
+			t.Errorf("Expected %s to be locked, got %s", room.ID, room.LockMode)
+		}
+	}
+}
+
+func TestLockRoomsCmd_Flags(t *testing.T) {
+	server := wstest.NewServer(wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"missing selector", []string{"lock"}, "at least one of the flags in the group [room all match] is required"},
+		{"several selectors", []string{"unlock", "--room", "Kitchen", "--all"}, "if any flags in the group [room all match] are set none of the others can be"},
+		{"empty room", []string{"lock", "--room", ""}, "the room must not be empty"},
+		{"empty pattern", []string{"unlock", "--match", ""}, "the pattern must not be empty"},
+		{"invalid pattern", []string{"lock", "--match", "["}, "invalid pattern"},
+		{"no rooms matching", []string{"lock", "--match", "Attic*"}, "no rooms selected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			before := server.Requests("UpdateConfig")
+
+			// Act
+			_, err := runCommand(t, server, append([]string{"rooms", tt.args[0], "--device-name", "devices/home"}, tt.args[1:]...)...)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+			if requests := server.Requests("UpdateConfig") - before; requests != 0 {
+				t.Errorf("Expected no updates, got %d", requests)
+			}
+		})
+	}
+}
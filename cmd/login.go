@@ -4,9 +4,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"github.com/zmoog/ws/v2/feedback"
-	"github.com/zmoog/ws/v2/ws/identity"
 )
 
 var loginCmd = &cobra.Command{
@@ -15,15 +13,9 @@ var loginCmd = &cobra.Command{
 	Long:  `Login to the Wavin API.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		feedback.Println("Login to the Wavin API")
-		im := identity.NewManager(
-			identity.Config{
-				Username:  viper.GetString("username"),
-				Password:  viper.GetString("password"),
-				WebApiKey: viper.GetString("web_api_key"),
-			},
-		)
+		im := newIdentityManager()
 
-		token, err := im.GetToken()
+		token, err := im.GetTokenContext(cmd.Context())
 		if err != nil {
 			return fmt.Errorf("failed to get token: %w", err)
 		}
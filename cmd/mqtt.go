@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/spf13/cobra"
+	"github.com/zmoog/ws/v2/feedback"
+	"github.com/zmoog/ws/v2/mqttbridge"
+)
+
+var (
+	mqttBroker          string
+	mqttUsername        string
+	mqttPassword        string
+	mqttClientID        string
+	mqttDiscoveryPrefix string
+	mqttTopicPrefix     string
+	mqttInterval        time.Duration
+)
+
+// mqttCmd represents the mqtt command
+var mqttCmd = &cobra.Command{
+	Use:   "mqtt",
+	Short: "Run an MQTT bridge for Home Assistant",
+	Long: `Run an MQTT bridge publishing each room in your account as a Home Assistant
+climate entity, using MQTT discovery.
+
+The state of the rooms is published at regular intervals, and the desired
+temperature set from Home Assistant is sent to the device.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if mqttInterval <= 0 {
+			return fmt.Errorf("invalid interval: %s", mqttInterval)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+		opts := mqtt.NewClientOptions().
+			AddBroker(mqttBroker).
+			SetClientID(mqttClientID).
+			SetUsername(mqttUsername).
+			SetPassword(mqttPassword).
+			SetAutoReconnect(true).
+			SetOrderMatters(false).
+			SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+				logger.Warn("connection to the broker lost", slog.Any("error", err))
+			})
+
+		bridge := mqttbridge.New(newClient(), mqttbridge.Config{
+			DiscoveryPrefix: mqttDiscoveryPrefix,
+			TopicPrefix:     mqttTopicPrefix,
+			Logger:          logger,
+		})
+		bridge.Configure(opts)
+
+		client := mqtt.NewClient(opts)
+		token := client.Connect()
+		token.Wait()
+		if err := token.Error(); err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", mqttBroker, err)
+		}
+		defer client.Disconnect(250)
+
+		feedback.Error(fmt.Sprintf("Connected to %s", mqttBroker))
+
+		return bridge.Run(ctx, client, mqttInterval)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mqttCmd)
+
+	mqttCmd.Flags().StringVarP(&mqttBroker, "broker", "b", "tcp://localhost:1883", "The MQTT broker URL")
+	mqttCmd.Flags().StringVar(&mqttUsername, "mqtt-username", "", "The username to connect to the broker")
+	mqttCmd.Flags().StringVar(&mqttPassword, "mqtt-password", "", "The password to connect to the broker")
+	mqttCmd.Flags().StringVar(&mqttClientID, "client-id", "ws-bridge", "The MQTT client ID")
+	mqttCmd.Flags().StringVar(&mqttDiscoveryPrefix, "discovery-prefix", mqttbridge.DefaultDiscoveryPrefix, "The Home Assistant discovery prefix")
+	mqttCmd.Flags().StringVar(&mqttTopicPrefix, "topic-prefix", mqttbridge.DefaultTopicPrefix, "The prefix of the state and command topics")
+	mqttCmd.Flags().DurationVarP(&mqttInterval, "interval", "i", time.Minute, "Polling interval")
+}
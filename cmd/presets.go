@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/zmoog/ws/v2/feedback"
+	"github.com/zmoog/ws/v2/ws"
+)
+
+var (
+	presetType string
+)
+
+// presetsCmd represents the presets command
+var presetsCmd = &cobra.Command{
+	Use:   "presets",
+	Short: "Manage temperature presets",
+	Long:  `Manage the eco, comfort and extra comfort temperature presets of the rooms.`,
+}
+
+// listPresetsCmd represents the presets list command
+var listPresetsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the temperature presets",
+	Long: `List the temperature presets of every room, or of a single room with
+--room, for each heating/cooling mode.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+
+		device, err := client.GetDeviceContext(cmd.Context(), ulc)
+		if err != nil {
+			return fmt.Errorf("failed to get device: %w", err)
+		}
+
+		rooms := device.LastConfig.Sentio.Rooms
+		if room != "" {
+			r, ok := device.Room(room)
+			if !ok {
+				return fmt.Errorf("%w: %s", ws.ErrRoomNotFound, room)
+			}
+			rooms = []ws.Room{r}
+		}
+
+		return feedback.PrintResult(presetsResult{rooms: rooms})
+	},
+}
+
+// setPresetCmd represents the presets set command
+var setPresetCmd = &cobra.Command{
+	Use:   "set TEMPERATURE",
+	Short: "Set the temperature of a preset",
+	Long: `Set the temperature of a preset in one room (--room), all the rooms (--all),
+or the rooms with a title matching a pattern (--match), for the current
+heating/cooling mode of the device. Without a selector, the preset is set
+in all the rooms.
+
+The temperature must be within the preset's minimum and maximum setpoint
+temperature in every selected room.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		temperature, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return fmt.Errorf("invalid temperature: %s", args[0])
+		}
+
+		preset, err := parsePresetType(presetType)
+		if err != nil {
+			return err
+		}
+
+		client := newClient()
+
+		rooms, err := selectedRooms(cmd, client)
+		if err != nil {
+			return err
+		}
+
+		updated, err := client.SetPresetTemperatureContext(cmd.Context(), ulc, preset, temperature, rooms...)
+		if err != nil {
+			return fmt.Errorf("failed to set preset temperature: %w", err)
+		}
+
+		return feedback.PrintResult(presetsResult{rooms: updated})
+	},
+}
+
+// activatePresetCmd represents the rooms activate-preset command
+var activatePresetCmd = &cobra.Command{
+	Use:   "activate-preset PRESET",
+	Short: "Switch a room to a temperature preset",
+	Long: `Switch a room to a temperature preset, setting the desired temperature to
+the preset one for the current heating/cooling mode of the device.
+
+The preset can be the full name (e.g. PRESET_TYPE_ECO) or the short one
+(eco, comfort or extra-comfort).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		preset, err := parsePresetType(args[0])
+		if err != nil {
+			return err
+		}
+
+		client := newClient()
+
+		updated, err := client.ActivatePresetContext(cmd.Context(), ulc, room, preset)
+		if err != nil {
+			return fmt.Errorf("failed to activate preset: %w", err)
+		}
+
+		return feedback.PrintResult(roomResult{room: updated.Room, hcMode: updated.HcMode})
+	},
+}
+
+// parsePresetType returns the preset type for a full or short preset
+// name, e.g. "PRESET_TYPE_EXTRA_COMFORT" or "extra-comfort".
+func parsePresetType(value string) (ws.PresetType, error) {
+	name := strings.ToUpper(strings.ReplaceAll(value, "-", "_"))
+	if !strings.HasPrefix(name, "PRESET_TYPE_") {
+		name = "PRESET_TYPE_" + name
+	}
+
+	preset := ws.PresetType(name)
+	if !preset.IsKnown() {
+		return "", fmt.Errorf("unknown preset %q, use eco, comfort or extra-comfort", value)
+	}
+
+	return preset, nil
+}
+
+type presetsResult struct {
+	rooms []ws.Room
+}
+
+// roomPresets are the temperature presets of a room.
+type roomPresets struct {
+	ID      string                 `json:"id"`
+	Title   string                 `json:"title"`
+	Presets []ws.TemperaturePreset `json:"presets"`
+}
+
+func (r presetsResult) Table() string {
+	table := pterm.TableData{{
+		"Name",
+		"Heating/cooling mode",
+		"Preset",
+		"Temperature",
+		"Temperature (min)",
+		"Temperature (max)",
+	}}
+	for _, room := range r.rooms {
+		for _, preset := range room.TemperaturePresets {
+			table = append(table, []string{
+				room.Title,
+				preset.HcMode.String(),
+				preset.Type.String(),
+				fmt.Sprintf("%.1f", preset.SetpointTemperature),
+				fmt.Sprintf("%.1f", preset.MinSetpointTemperature),
+				fmt.Sprintf("%.1f", preset.MaxSetpointTemperature),
+			})
+		}
+	}
+
+	rendered, err := pterm.DefaultTable.WithHasHeader().WithData(table).Srender()
+	if err != nil {
+		return fmt.Sprintf("failed to render table: %s", err)
+	}
+
+	return rendered + "\n"
+}
+
+func (r presetsResult) String() string {
+	return r.Table()
+}
+
+func (r presetsResult) Data() any {
+	data := make([]roomPresets, 0, len(r.rooms))
+	for _, room := range r.rooms {
+		data = append(data, roomPresets{ID: room.ID, Title: room.Title, Presets: room.TemperaturePresets})
+	}
+
+	return data
+}
+
+func init() {
+	rootCmd.AddCommand(presetsCmd)
+
+	presetsCmd.AddCommand(listPresetsCmd)
+	presetsCmd.AddCommand(setPresetCmd)
+
+	listPresetsCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	listPresetsCmd.Flags().StringVarP(&room, "room", "r", "", "Room ID or title (default to all the rooms)")
+	_ = listPresetsCmd.MarkFlagRequired("device-name")
+
+	setPresetCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	setPresetCmd.Flags().StringVarP(&presetType, "type", "t", "", "Preset type (e.g. PRESET_TYPE_ECO or eco)")
+	_ = setPresetCmd.MarkFlagRequired("device-name")
+	_ = setPresetCmd.MarkFlagRequired("type")
+	addRoomSelectorFlags(setPresetCmd)
+
+	roomsCmd.AddCommand(activatePresetCmd)
+
+	activatePresetCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	activatePresetCmd.Flags().StringVarP(&room, "room", "r", "", "Room ID or title")
+	_ = activatePresetCmd.MarkFlagRequired("device-name")
+	_ = activatePresetCmd.MarkFlagRequired("room")
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zmoog/ws/v2/ws"
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+func TestSetPresetCmd(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{"all the rooms by default", nil, []string{"room-1", "room-2"}},
+		{"one room", []string{"--room", "Bedroom"}, []string{"room-2"}},
+		{"matching rooms", []string{"--match", "kitch*"}, []string{"room-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := wstest.NewServer(wstest.NewDevice("devices/home",
+				wstest.NewRoom("room-1", "Kitchen"),
+				wstest.NewRoom("room-2", "Bedroom"),
+			))
+			defer server.Close()
+
+			// Act
+			out, err := runCommand(t, server, append([]string{"presets", "set", "--device-name", "devices/home", "--type", "eco", "17.5", "--output", "json"}, tt.args...)...)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			var rooms []roomPresets
+			if err := json.Unmarshal([]byte(out), &rooms); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			var ids []string
+			for _, room := range rooms {
+				ids = append(ids, room.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected rooms %v, got %v", tt.expected, ids)
+			}
+
+			device, _ := server.Device("devices/home")
+			for _, id := range tt.expected {
+				room, _ := device.Room(id)
+				if preset, _ := room.TemperaturePreset(ws.PresetTypeEco, ws.HcModeHeating); preset.SetpointTemperature != 17.5 {
+					t.Errorf("Expected eco preset 17.5 in %s, got %.1f", id, preset.SetpointTemperature)
+				}
+			}
+		})
+	}
+}
+
+func TestPresetsCmd_Flags(t *testing.T) {
+	server := wstest.NewServer(wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"missing type", []string{"presets", "set", "17.5"}, `required flag(s) "type" not set`},
+		{"unknown type", []string{"presets", "set", "--type", "warm", "17.5"}, `unknown preset "warm"`},
+		{"missing temperature", []string{"presets", "set", "--type", "eco"}, "accepts 1 arg(s), received 0"},
+		{"invalid temperature", []string{"presets", "set", "--type", "eco", "cold"}, "invalid temperature: cold"},
+		{"empty room", []string{"presets", "set", "--type", "eco", "--room", "", "17.5"}, "the room must not be empty"},
+		{"several selectors", []string{"presets", "set", "--type", "eco", "--all", "--match", "K*", "17.5"}, "if any flags in the group [room all match] are set none of the others can be"},
+		{"out of range", []string{"presets", "set", "--type", "eco", "45"}, ws.ErrOutOfRange.Error()},
+		{"activate unknown preset", []string{"rooms", "activate-preset", "--room", "Kitchen", "warm"}, `unknown preset "warm"`},
+		{"activate in unknown room", []string{"rooms", "activate-preset", "--room", "Attic", "eco"}, "room not found: Attic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			before := server.Requests("UpdateConfig")
+
+			// Act
+			_, err := runCommand(t, server, append(tt.args, "--device-name", "devices/home")...)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+			if requests := server.Requests("UpdateConfig") - before; requests != 0 {
+				t.Errorf("Expected no updates, got %d", requests)
+			}
+		})
+	}
+}
@@ -5,20 +5,27 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"github.com/zmoog/ws/v2/feedback"
+	"github.com/zmoog/ws/v2/history"
 	"github.com/zmoog/ws/v2/ws"
-	"github.com/zmoog/ws/v2/ws/identity"
 )
 
 var (
-	ulc string
+	ulc       string
+	room      string
+	sparkSpan time.Duration
 )
 
+// sparklineWidth is the number of characters of the sparklines in the
+// rooms table.
+const sparklineWidth = 24
+
 // roomsCmd represents the rooms command
 var roomsCmd = &cobra.Command{
 	Use:   "rooms",
@@ -30,57 +37,145 @@ var roomsCmd = &cobra.Command{
 var listRoomsCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List the rooms",
-	Long:  `List the rooms in a location.`,
+	Long: `List the rooms in a location.
+
+With --sparkline, the table has a column with the trend of the air
+temperature of each room over the given period (e.g. 6h), from the
+history recorded by the record command.`,
+	Annotations: map[string]string{annotationTabular: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		identityManager := identity.NewManager(
-			identity.Config{
-				Username:  viper.GetString("username"),
-				Password:  viper.GetString("password"),
-				WebApiKey: viper.GetString("web_api_key"),
-			},
-		)
-		client := ws.NewClient(
-			identityManager,
-			viper.GetString("api_endpoint"),
-		)
-
-		device, err := client.GetDevice(ulc)
+		if sparkSpan < 0 {
+			return fmt.Errorf("invalid sparkline period: %s", sparkSpan)
+		}
+
+		client := newClient()
+
+		device, err := client.GetDeviceContext(cmd.Context(), ulc)
 		if err != nil {
 			return fmt.Errorf("failed to get device: %w", err)
 		}
 
-		_ = feedback.PrintResult(roomsListResult{device: device})
+		result := roomsListResult{device: device}
+		if sparkSpan > 0 {
+			result.trends, err = roomTrends(device.Name, sparkSpan)
+			if err != nil {
+				return err
+			}
+		}
 
-		return nil
+		return feedback.PrintResult(result)
 	},
 }
 
+// setTemperatureCmd represents the set-temperature command
+var setTemperatureCmd = &cobra.Command{
+	Use:   "set-temperature TEMPERATURE",
+	Short: "Set the desired temperature of a room",
+	Long: `Set the desired temperature of a room.
+
+The room can be identified by ID or title, and the temperature must be
+within the room's minimum and maximum setpoint temperature.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		temperature, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return fmt.Errorf("invalid temperature: %s", args[0])
+		}
+
+		client := newClient()
+
+		updated, err := client.SetRoomTemperatureContext(cmd.Context(), ulc, room, temperature)
+		if err != nil {
+			return fmt.Errorf("failed to set temperature: %w", err)
+		}
+
+		return feedback.PrintResult(roomResult{room: updated.Room, hcMode: updated.HcMode})
+	},
+}
+
+// setHumidityCmd represents the set-humidity command
+var setHumidityCmd = &cobra.Command{
+	Use:   "set-humidity HUMIDITY",
+	Short: "Set the desired humidity of a room",
+	Long: `Set the dehumidification setpoint of a room for the current
+heating/cooling mode of the device.
+
+The room can be identified by ID or title, and the humidity must be within
+the minimum and maximum setpoint of the dehumidification preset.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		humidity, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return fmt.Errorf("invalid humidity: %s", args[0])
+		}
+
+		client := newClient()
+
+		updated, err := client.SetRoomHumidityContext(cmd.Context(), ulc, room, humidity)
+		if err != nil {
+			return fmt.Errorf("failed to set humidity: %w", err)
+		}
+
+		return feedback.PrintResult(humidityResult{room: updated.Room})
+	},
+}
+
+// roomTrends returns the sparklines of the air temperature of the rooms
+// of a device over the last span, keyed by room ID.
+func roomTrends(deviceName string, span time.Duration) (map[string]string, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	until := time.Now()
+	since := until.Add(-span)
+
+	samples, err := history.NewStore(path).Query(history.Query{Device: deviceName, Since: since})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+
+	byRoom := make(map[string][]history.Sample)
+	for _, sample := range samples {
+		byRoom[sample.RoomID] = append(byRoom[sample.RoomID], sample)
+	}
+
+	trends := make(map[string]string, len(byRoom))
+	for id, samples := range byRoom {
+		trends[id] = sparkline(history.Series(samples, history.AirTemperature, since, until, sparklineWidth))
+	}
+
+	return trends, nil
+}
+
+// roomsListResult is the rooms of a device. The trends are the
+// sparklines of the rooms, keyed by ID, shown in an extra column
+// when not nil.
 type roomsListResult struct {
 	device ws.Device
+	trends map[string]string
 }
 
 func (r roomsListResult) Table() string {
 	var sb strings.Builder
 
-	table := pterm.TableData{}
-	table = append(table, []string{
-		"Name",
-		"Temperature state",
-		"Temperature (desired)",
-		"Temperature (current)",
-		"Humidity (current)",
-		"Dehumidification state",
-	})
+	sentio := r.device.LastConfig.Sentio
 
-	for _, room := range r.device.LastConfig.Sentio.Rooms {
-		table = append(table, []string{
-			room.Title,
-			room.TemperatureState,
-			fmt.Sprintf("%.1f", room.SetpointTemperature),
-			fmt.Sprintf("%.1f", room.AirTemperature),
-			fmt.Sprintf("%.1f", room.Humidity),
-			room.DehumidifierState,
-		})
+	table := pterm.TableData{roomsTableHeader()}
+	if r.trends != nil {
+		table[0] = append(table[0], fmt.Sprintf("Trend (%s)", formatSpan(sparkSpan)))
+	}
+	for _, room := range sentio.Rooms {
+		row := roomsTableRow(room, sentio.HcMode)
+		if r.trends != nil {
+			trend, ok := r.trends[room.ID]
+			if !ok {
+				trend = "-"
+			}
+			row = append(row, trend)
+		}
+		table = append(table, row)
 	}
 
 	rendered, err := pterm.DefaultTable.WithHasHeader().WithData(table).Srender()
@@ -99,6 +194,39 @@ func (r roomsListResult) Table() string {
 	return sb.String()
 }
 
+func (r roomsListResult) Rows() ([]string, [][]string) {
+	header := append([]string{"ID"}, roomsTableHeader()...)
+
+	sentio := r.device.LastConfig.Sentio
+
+	records := make([][]string, 0, len(sentio.Rooms))
+	for _, room := range sentio.Rooms {
+		var humiditySetpoint string
+		if preset, ok := room.DehumidificationPreset(sentio.HcMode); ok {
+			humiditySetpoint = formatFloat(preset.Setpoint)
+		}
+
+		records = append(records, []string{
+			room.ID,
+			room.Title,
+			string(room.TemperatureState),
+			formatFloat(room.SetpointTemperature),
+			formatFloat(room.AirTemperature),
+			formatFloat(room.Humidity),
+			humiditySetpoint,
+			string(room.DehumidifierState),
+		})
+	}
+
+	return header, records
+}
+
+// formatFloat formats a value for the rows of a result, using the
+// shortest representation, e.g. "21" or "20.5".
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
 func (r roomsListResult) String() string {
 	return r.Table()
 }
@@ -107,6 +235,116 @@ func (r roomsListResult) Data() any {
 	return r.device.LastConfig.Sentio.Rooms
 }
 
+// roomResult is a single room. The heating/cooling mode of the device is
+// used to show the active humidity setpoint.
+type roomResult struct {
+	room   ws.Room
+	hcMode ws.HcMode
+}
+
+func (r roomResult) Table() string {
+	rendered, err := renderRoomsTable([]ws.Room{r.room}, r.hcMode)
+	if err != nil {
+		return fmt.Sprintf("failed to render table: %s", err)
+	}
+
+	return rendered + "\n"
+}
+
+func (r roomResult) String() string {
+	return r.Table()
+}
+
+func (r roomResult) Data() any {
+	return r.room
+}
+
+// humidityResult shows the dehumidification presets of a room.
+type humidityResult struct {
+	room ws.Room
+}
+
+func (r humidityResult) Table() string {
+	table := pterm.TableData{{
+		"Name",
+		"Heating/cooling mode",
+		"Humidity (desired)",
+		"Humidity (min)",
+		"Humidity (max)",
+		"Humidity (current)",
+		"Dehumidification state",
+	}}
+	for _, preset := range r.room.DehumidificationPresets {
+		table = append(table, []string{
+			r.room.Title,
+			preset.HcMode.String(),
+			fmt.Sprintf("%.1f", preset.Setpoint),
+			fmt.Sprintf("%.1f", preset.MinHumiditySetpoint),
+			fmt.Sprintf("%.1f", preset.MaxHumiditySetpoint),
+			fmt.Sprintf("%.1f", r.room.Humidity),
+			r.room.DehumidifierState.String(),
+		})
+	}
+
+	rendered, err := pterm.DefaultTable.WithHasHeader().WithData(table).Srender()
+	if err != nil {
+		return fmt.Sprintf("failed to render table: %s", err)
+	}
+
+	return rendered + "\n"
+}
+
+func (r humidityResult) String() string {
+	return r.Table()
+}
+
+func (r humidityResult) Data() any {
+	return r.room
+}
+
+// renderRoomsTable renders the rooms as a table.
+func renderRoomsTable(rooms []ws.Room, hcMode ws.HcMode) (string, error) {
+	table := pterm.TableData{roomsTableHeader()}
+	for _, room := range rooms {
+		table = append(table, roomsTableRow(room, hcMode))
+	}
+
+	return pterm.DefaultTable.WithHasHeader().WithData(table).Srender()
+}
+
+// roomsTableHeader returns the header of the rooms table.
+func roomsTableHeader() []string {
+	return []string{
+		"Name",
+		"Temperature state",
+		"Temperature (desired)",
+		"Temperature (current)",
+		"Humidity (current)",
+		"Humidity (desired)",
+		"Dehumidification state",
+	}
+}
+
+// roomsTableRow returns the row of the rooms table for a room. The
+// desired humidity is the setpoint of the dehumidification preset for
+// the heating/cooling mode, if any.
+func roomsTableRow(room ws.Room, hcMode ws.HcMode) []string {
+	humiditySetpoint := "-"
+	if preset, ok := room.DehumidificationPreset(hcMode); ok {
+		humiditySetpoint = fmt.Sprintf("%.1f", preset.Setpoint)
+	}
+
+	return []string{
+		room.Title,
+		room.TemperatureState.String(),
+		fmt.Sprintf("%.1f", room.SetpointTemperature),
+		fmt.Sprintf("%.1f", room.AirTemperature),
+		fmt.Sprintf("%.1f", room.Humidity),
+		humiditySetpoint,
+		room.DehumidifierState.String(),
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(roomsCmd)
 
@@ -122,5 +360,21 @@ func init() {
 	roomsCmd.AddCommand(listRoomsCmd)
 
 	listRoomsCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	listRoomsCmd.Flags().DurationVar(&sparkSpan, "sparkline", 0, "Show the air temperature trend over this period (e.g. 6h)")
+	listRoomsCmd.Flags().StringVar(&historyDB, "db", "", "The history database (default is $HOME/.ws/history.db)")
 	_ = listRoomsCmd.MarkFlagRequired("device-name")
+
+	roomsCmd.AddCommand(setTemperatureCmd)
+
+	setTemperatureCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	setTemperatureCmd.Flags().StringVarP(&room, "room", "r", "", "Room ID or title")
+	_ = setTemperatureCmd.MarkFlagRequired("device-name")
+	_ = setTemperatureCmd.MarkFlagRequired("room")
+
+	roomsCmd.AddCommand(setHumidityCmd)
+
+	setHumidityCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	setHumidityCmd.Flags().StringVarP(&room, "room", "r", "", "Room ID or title")
+	_ = setHumidityCmd.MarkFlagRequired("device-name")
+	_ = setHumidityCmd.MarkFlagRequired("room")
 }
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zmoog/ws/v2/ws"
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+func TestSetTemperatureCmd(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")))
+	defer server.Close()
+
+	// Act
+	out, err := runCommand(t, server, "rooms", "set-temperature", "--device-name", "devices/home", "--room", "Kitchen", "22.5", "--output", "json")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var room ws.Room
+	if err := json.Unmarshal([]byte(out), &room); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if room.SetpointTemperature != 22.5 {
+		t.Errorf("Expected setpoint 22.5, got %.1f", room.SetpointTemperature)
+	}
+	if requests := server.Requests("GetDevice"); requests != 1 {
+		t.Errorf("Expected 1 GetDevice request, got %d", requests)
+	}
+}
+
+func TestSetHumidityCmd(t *testing.T) {
+	// Arrange
+	device := wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen"))
+	device.LastConfig.Sentio.HcMode = ws.HcModeCooling
+	server := wstest.NewServer(device)
+	defer server.Close()
+
+	// Act
+	out, err := runCommand(t, server, "rooms", "set-humidity", "--device-name", "devices/home", "--room", "Kitchen", "50", "--output", "json")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var room ws.Room
+	if err := json.Unmarshal([]byte(out), &room); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if preset, _ := room.DehumidificationPreset(ws.HcModeCooling); preset.Setpoint != 50 {
+		t.Errorf("Expected humidity setpoint 50, got %.1f", preset.Setpoint)
+	}
+}
+
+func TestSetHumidityCmd_Flags(t *testing.T) {
+	server := wstest.NewServer(wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"missing room", []string{"50"}, `required flag(s) "room" not set`},
+		{"missing humidity", []string{"--room", "Kitchen"}, "accepts 1 arg(s), received 0"},
+		{"invalid humidity", []string{"--room", "Kitchen", "humid"}, "invalid humidity: humid"},
+		{"unknown room", []string{"--room", "Attic", "50"}, "room not found: Attic"},
+		{"no preset for the mode", []string{"--room", "Kitchen", "50"}, ws.ErrNoDehumidificationPreset.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			before := server.Requests("UpdateConfig")
+
+			// Act
+			_, err := runCommand(t, server, append([]string{"rooms", "set-humidity", "--device-name", "devices/home"}, tt.args...)...)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+			if requests := server.Requests("UpdateConfig") - before; requests != 0 {
+				t.Errorf("Expected no updates, got %d", requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/zmoog/ws/v2/feedback"
+	"github.com/zmoog/ws/v2/ws/identity/identitytest"
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+// runCommand runs the ws command with args against the server, signing
+// in to a fake identity server, and returns the printed output.
+func runCommand(t *testing.T, server *wstest.Server, args ...string) (string, error) {
+	t.Helper()
+
+	identityServer := identitytest.NewServer()
+	defer identityServer.Close()
+	identityServer.AddUser("user@example.com", "secret")
+
+	t.Setenv("HOME", t.TempDir())
+
+	var out bytes.Buffer
+	feedback.SetDefault(feedback.New(&out, &bytes.Buffer{}, feedback.Table))
+	defer feedback.SetDefault(feedback.Default())
+
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	defer rootCmd.SetOut(nil)
+	defer rootCmd.SetErr(nil)
+
+	// The flags keep their values between runs.
+	resetFlags(rootCmd)
+
+	rootCmd.SetArgs(append(args,
+		"--api-endpoint", server.URL,
+		"--identity-endpoint", identityServer.URL,
+		"--web-api-key", identitytest.APIKey,
+		"--username", "user@example.com",
+		"--password", "secret",
+	))
+
+	err := rootCmd.Execute()
+
+	return out.String(), err
+}
+
+// resetFlags restores the default values of the flags of cmd and its
+// subcommands.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+func TestRootCmd_TabularOutput(t *testing.T) {
+	server := wstest.NewServer(wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		err      error
+		requests int
+		expected string
+	}{
+		{
+			name:     "list rooms",
+			args:     []string{"rooms", "list", "--device-name", "devices/home"},
+			requests: 1,
+			expected: "ID,Name,",
+		},
+		{
+			name: "set temperature",
+			args: []string{"rooms", "set-temperature", "--device-name", "devices/home", "--room", "Kitchen", "21"},
+			err:  feedback.ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			before := server.Requests("GetDevice") + server.Requests("UpdateConfig")
+
+			// Act
+			out, err := runCommand(t, server, append(tt.args, "--output", "csv")...)
+
+			// Assert
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected %v, got %v", tt.err, err)
+			}
+			if requests := server.Requests("GetDevice") + server.Requests("UpdateConfig") - before; requests != tt.requests {
+				t.Errorf("Expected %d requests, got %d", tt.requests, requests)
+			}
+			if !strings.HasPrefix(out, tt.expected) {
+				t.Errorf("Expected output starting with %q, got %q", tt.expected, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/zmoog/ws/v2/feedback"
+	"github.com/zmoog/ws/v2/ws"
+)
+
+// scheduleCmd represents the schedule command
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage weekly schedules",
+	Long:  `Manage the weekly schedules of the rooms.`,
+}
+
+// showScheduleCmd represents the schedule show command
+var showScheduleCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the weekly schedule of a room",
+	Long: `Show the weekly schedule of a room.
+
+The preset timeframes of each day are shown as returned by the device.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+
+		device, err := client.GetDeviceContext(cmd.Context(), ulc)
+		if err != nil {
+			return fmt.Errorf("failed to get device: %w", err)
+		}
+
+		r, ok := device.Room(room)
+		if !ok {
+			return fmt.Errorf("%w: %s", ws.ErrRoomNotFound, room)
+		}
+
+		return feedback.PrintResult(scheduleResult{room: r})
+	},
+}
+
+type scheduleResult struct {
+	room ws.Room
+}
+
+func (r scheduleResult) Table() string {
+	schedule := r.room.WeeklySchedule
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Room: %s\n", r.room.Title))
+	sb.WriteString(fmt.Sprintf("Schedule mode: %s\n", schedule.ScheduleMode))
+	sb.WriteString(fmt.Sprintf("Default preset: %s\n\n", schedule.DefaultPresetType))
+
+	table := pterm.TableData{{"Day", "Preset timeframes"}}
+	for _, interval := range schedule.Intervals {
+		table = append(table, []string{
+			interval.Day.String(),
+			strings.Join(interval.PresetTimeframes, ", "),
+		})
+	}
+
+	rendered, err := pterm.DefaultTable.WithHasHeader().WithData(table).Srender()
+	if err != nil {
+		return fmt.Sprintf("failed to render table: %s", err)
+	}
+	sb.WriteString(rendered)
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+func (r scheduleResult) String() string {
+	return r.Table()
+}
+
+func (r scheduleResult) Data() any {
+	return r.room.WeeklySchedule
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+
+	scheduleCmd.AddCommand(showScheduleCmd)
+
+	showScheduleCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	showScheduleCmd.Flags().StringVarP(&room, "room", "r", "", "Room ID or title")
+	_ = showScheduleCmd.MarkFlagRequired("device-name")
+	_ = showScheduleCmd.MarkFlagRequired("room")
+}
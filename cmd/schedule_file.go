@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zmoog/ws/v2/ws"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	scheduleFilename string
+)
+
+// scheduleFile is the YAML document used to export the weekly schedule
+// of a room, with the preset timeframes as returned by the API.
+type scheduleFile struct {
+	Device            string `yaml:"device"`
+	Room              string `yaml:"room"`
+	ws.WeeklySchedule `yaml:",inline"`
+}
+
+// exportScheduleCmd represents the schedule export command
+var exportScheduleCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the weekly schedule of a room to YAML",
+	Long: `Export the weekly schedule of a room to a YAML file, e.g. to keep it in git.
+
+The preset timeframes are written as returned by the device. Without --file,
+the YAML document is written to the standard output.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+
+		device, err := client.GetDeviceContext(cmd.Context(), ulc)
+		if err != nil {
+			return fmt.Errorf("failed to get device: %w", err)
+		}
+
+		r, ok := device.Room(room)
+		if !ok {
+			return fmt.Errorf("%w: %s", ws.ErrRoomNotFound, room)
+		}
+
+		file := scheduleFile{Device: device.Name, Room: r.ID, WeeklySchedule: r.WeeklySchedule}
+		if scheduleFilename == "" || scheduleFilename == "-" {
+			return writeScheduleFile(os.Stdout, file)
+		}
+
+		f, err := os.Create(scheduleFilename)
+		if err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+
+		if err := writeScheduleFile(f, file); err != nil {
+			_ = f.Close()
+			return err
+		}
+
+		// Closing can fail to flush the file, leaving a truncated export.
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write schedule: %w", err)
+		}
+
+		return nil
+	},
+}
+
+// writeScheduleFile encodes a schedule file as YAML.
+func writeScheduleFile(out io.Writer, file scheduleFile) error {
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(file); err != nil {
+		return fmt.Errorf("failed to write schedule: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to write schedule: %w", err)
+	}
+
+	return nil
+}
+
+func init() {
+	scheduleCmd.AddCommand(exportScheduleCmd)
+
+	exportScheduleCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	exportScheduleCmd.Flags().StringVarP(&room, "room", "r", "", "Room ID or title")
+	exportScheduleCmd.Flags().StringVarP(&scheduleFilename, "file", "f", "", "Output file (default to standard output)")
+	_ = exportScheduleCmd.MarkFlagRequired("device-name")
+	_ = exportScheduleCmd.MarkFlagRequired("room")
+}
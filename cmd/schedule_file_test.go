@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zmoog/ws/v2/ws/wstest"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExportScheduleCmd(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")))
+	defer server.Close()
+
+	filename := filepath.Join(t.TempDir(), "kitchen.yaml")
+
+	// Act
+	_, err := runCommand(t, server, "schedule", "export", "--device-name", "devices/home", "--room", "Kitchen", "--file", filename)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var file scheduleFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if file.Device != "devices/home" || file.Room != "room-1" {
+		t.Errorf("Expected devices/home and room-1, got %s and %s", file.Device, file.Room)
+	}
+	if !reflect.DeepEqual(file.WeeklySchedule, wstest.NewWeeklySchedule()) {
+		t.Errorf("Expected the schedule as returned by the device, got %+v", file.WeeklySchedule)
+	}
+}
+
+func TestExportScheduleCmd_Flags(t *testing.T) {
+	server := wstest.NewServer(wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"missing room", []string{"--device-name", "devices/home"}, `required flag(s) "room" not set`},
+		{"unknown room", []string{"--device-name", "devices/home", "--room", "Attic"}, "room not found: Attic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := runCommand(t, server, append([]string{"schedule", "export"}, tt.args...)...)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
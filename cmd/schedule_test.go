@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/zmoog/ws/v2/ws"
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+func TestShowScheduleCmd(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")))
+	defer server.Close()
+
+	// Act
+	out, err := runCommand(t, server, "schedule", "show", "--device-name", "devices/home", "--room", "Kitchen", "--output", "json")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var schedule ws.WeeklySchedule
+	if err := json.Unmarshal([]byte(out), &schedule); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(schedule, wstest.NewWeeklySchedule()) {
+		t.Errorf("Expected the schedule as returned by the device, got %+v", schedule)
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zmoog/ws/v2/ws"
+)
+
+var (
+	allRooms   bool
+	roomsMatch string
+)
+
+// addRoomSelectorFlags adds the flags selecting one room (--room), all
+// the rooms (--all) or the rooms matching a pattern (--match). Commands
+// requiring an explicit selection mark one of them as required.
+func addRoomSelectorFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&room, "room", "r", "", "Room ID or title")
+	cmd.Flags().BoolVar(&allRooms, "all", false, "Select all the rooms")
+	cmd.Flags().StringVar(&roomsMatch, "match", "", "Select the rooms with a title matching the pattern (e.g. \"Bedroom*\")")
+	cmd.MarkFlagsMutuallyExclusive("room", "all", "match")
+}
+
+// selectedRooms returns the rooms selected by the room selector flags
+// of cmd. Without --room or --match, it returns all the rooms, as
+// selected by --all or by default for the commands not requiring a
+// selector. The device is fetched only when selecting all or matching
+// rooms.
+func selectedRooms(cmd *cobra.Command, client *ws.Client) ([]string, error) {
+	if cmd.Flags().Changed("room") {
+		if room == "" {
+			return nil, errors.New("invalid room: the room must not be empty")
+		}
+		return []string{room}, nil
+	}
+
+	if cmd.Flags().Changed("match") && roomsMatch == "" {
+		return nil, errors.New("invalid pattern: the pattern must not be empty")
+	}
+
+	device, err := client.GetDeviceContext(cmd.Context(), ulc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device: %w", err)
+	}
+
+	return selectRooms(device, roomsMatch)
+}
+
+// selectRooms returns the IDs of the rooms with a title or personalized
+// title matching the pattern, case-insensitively. An empty pattern
+// selects all the rooms.
+func selectRooms(device ws.Device, pattern string) ([]string, error) {
+	pattern = strings.ToLower(pattern)
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	var ids []string
+	for _, r := range device.LastConfig.Sentio.Rooms {
+		if pattern == "" || matchTitle(pattern, r.Title) || matchTitle(pattern, r.TitlePersonalized) {
+			ids = append(ids, r.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("no rooms selected")
+	}
+
+	return ids, nil
+}
+
+func matchTitle(pattern, title string) bool {
+	matched, _ := path.Match(pattern, strings.ToLower(title))
+	return title != "" && matched
+}
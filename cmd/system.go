@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/zmoog/ws/v2/feedback"
+	"github.com/zmoog/ws/v2/ws"
+)
+
+// systemCmd represents the system command
+var systemCmd = &cobra.Command{
+	Use:   "system",
+	Short: "Manage the system settings",
+	Long:  `Manage the system settings of a device, like the heating/cooling, standby and quiet modes.`,
+}
+
+// hcModeCmd represents the system hc-mode command
+var hcModeCmd = &cobra.Command{
+	Use:   "hc-mode",
+	Short: "Manage the heating/cooling mode",
+	Long:  `Manage the heating/cooling mode of a device.`,
+}
+
+// setHcModeCmd represents the system hc-mode set command
+var setHcModeCmd = &cobra.Command{
+	Use:   "set MODE",
+	Short: "Set the heating/cooling mode",
+	Long: `Set the heating/cooling mode of a device.
+
+The mode must be one of the modes available on the device, either as the
+full name (e.g. HC_MODE_COOLING) or the short one (e.g. cooling).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+
+		device, err := client.SetHcModeContext(cmd.Context(), ulc, parseHcMode(args[0]))
+		if err != nil {
+			return fmt.Errorf("failed to set heating/cooling mode: %w", err)
+		}
+
+		return feedback.PrintResult(systemResult{device: device})
+	},
+}
+
+// standbyCmd represents the system standby command
+var standbyCmd = &cobra.Command{
+	Use:       "standby on|off",
+	Short:     "Turn the standby mode on or off",
+	Long:      `Turn the standby mode of a device on or off.`,
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"on", "off"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mode := ws.StandbyModeOff
+		if args[0] == "on" {
+			mode = ws.StandbyModeOn
+		}
+
+		client := newClient()
+
+		device, err := client.SetStandbyModeContext(cmd.Context(), ulc, mode)
+		if err != nil {
+			return fmt.Errorf("failed to set standby mode: %w", err)
+		}
+
+		return feedback.PrintResult(systemResult{device: device})
+	},
+}
+
+// quietCmd represents the system quiet command
+var quietCmd = &cobra.Command{
+	Use:   "quiet",
+	Short: "Manage the quiet mode",
+	Long:  `Manage the quiet mode of a device, reducing the noise of the heat pump.`,
+}
+
+// showQuietCmd represents the system quiet show command
+var showQuietCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the quiet mode",
+	Long:  `Show the quiet mode of a device.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+
+		device, err := client.GetDeviceContext(cmd.Context(), ulc)
+		if err != nil {
+			return fmt.Errorf("failed to get device: %w", err)
+		}
+
+		return feedback.PrintResult(quietResult{settings: device.LastConfig.Sentio.QuietSettings})
+	},
+}
+
+// setQuietCmd represents the system quiet set command
+var setQuietCmd = &cobra.Command{
+	Use:   "set MODE",
+	Short: "Set the quiet mode",
+	Long: `Set the quiet mode of a device, either as the full name
+(e.g. QUIET_MODE_ON) or the short one (e.g. on).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+
+		device, err := client.SetQuietModeContext(cmd.Context(), ulc, parseQuietMode(args[0]))
+		if err != nil {
+			return fmt.Errorf("failed to set quiet mode: %w", err)
+		}
+
+		return feedback.PrintResult(quietResult{settings: device.LastConfig.Sentio.QuietSettings})
+	},
+}
+
+// parseQuietMode returns the quiet mode for a full or short mode name,
+// e.g. "QUIET_MODE_ON" or "on".
+func parseQuietMode(value string) ws.QuietMode {
+	mode := strings.ToUpper(value)
+	if !strings.HasPrefix(mode, "QUIET_MODE_") {
+		mode = "QUIET_MODE_" + mode
+	}
+
+	return ws.QuietMode(mode)
+}
+
+// parseHcMode returns the heating/cooling mode for a full or short
+// mode name, e.g. "HC_MODE_COOLING" or "cooling".
+func parseHcMode(value string) ws.HcMode {
+	mode := strings.ToUpper(value)
+	if !strings.HasPrefix(mode, "HC_MODE_") {
+		mode = "HC_MODE_" + mode
+	}
+
+	return ws.HcMode(mode)
+}
+
+type systemResult struct {
+	device ws.Device
+}
+
+func (r systemResult) Table() string {
+	sentio := r.device.LastConfig.Sentio
+
+	available := make([]string, 0, len(sentio.AvailableHcModes))
+	for _, mode := range sentio.AvailableHcModes {
+		available = append(available, mode.String())
+	}
+
+	table := pterm.TableData{
+		{"Setting", "Value"},
+		{"Heating/cooling mode", sentio.HcMode.String()},
+		{"Available heating/cooling modes", strings.Join(available, ", ")},
+		{"Automatic heating/cooling mode", sentio.AutomaticHcMode.String()},
+		{"Standby mode", sentio.StandbyMode.String()},
+		{"Vacation mode", sentio.VacationSettings.VacationMode.String()},
+		{"Quiet mode", sentio.QuietSettings.Mode.String()},
+	}
+
+	rendered, err := pterm.DefaultTable.WithHasHeader().WithData(table).Srender()
+	if err != nil {
+		return fmt.Sprintf("failed to render table: %s", err)
+	}
+
+	return rendered + "\n"
+}
+
+func (r systemResult) String() string {
+	return r.Table()
+}
+
+func (r systemResult) Data() any {
+	return r.device.LastConfig
+}
+
+type quietResult struct {
+	settings ws.QuietSettings
+}
+
+func (r quietResult) Table() string {
+	table := pterm.TableData{
+		{"Quiet mode"},
+		{r.settings.Mode.String()},
+	}
+
+	rendered, err := pterm.DefaultTable.WithHasHeader().WithData(table).Srender()
+	if err != nil {
+		return fmt.Sprintf("failed to render table: %s", err)
+	}
+
+	return rendered + "\n"
+}
+
+func (r quietResult) String() string {
+	return r.Table()
+}
+
+func (r quietResult) Data() any {
+	return r.settings
+}
+
+func init() {
+	rootCmd.AddCommand(systemCmd)
+
+	systemCmd.AddCommand(hcModeCmd)
+	systemCmd.AddCommand(standbyCmd)
+	systemCmd.AddCommand(quietCmd)
+	hcModeCmd.AddCommand(setHcModeCmd)
+	quietCmd.AddCommand(showQuietCmd)
+	quietCmd.AddCommand(setQuietCmd)
+
+	setHcModeCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	_ = setHcModeCmd.MarkFlagRequired("device-name")
+
+	standbyCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	_ = standbyCmd.MarkFlagRequired("device-name")
+
+	showQuietCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	_ = showQuietCmd.MarkFlagRequired("device-name")
+
+	setQuietCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	_ = setQuietCmd.MarkFlagRequired("device-name")
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zmoog/ws/v2/ws"
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+func TestSystemCmd(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		assert func(t *testing.T, config ws.LastConfig)
+	}{
+		{"set heating/cooling mode", []string{"hc-mode", "set", "cooling"}, func(t *testing.T, config ws.LastConfig) {
+			if config.Sentio.HcMode != ws.HcModeCooling {
+				t.Errorf("Expected cooling mode, got %s", config.Sentio.HcMode)
+			}
+		}},
+		{"turn on standby", []string{"standby", "on"}, func(t *testing.T, config ws.LastConfig) {
+			if config.Sentio.StandbyMode != ws.StandbyModeOn {
+				t.Errorf("Expected standby mode on, got %s", config.Sentio.StandbyMode)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := wstest.NewServer(wstest.NewDevice("devices/home"))
+			defer server.Close()
+
+			// Act
+			out, err := runCommand(t, server, append(append([]string{"system"}, tt.args...), "--device-name", "devices/home", "--output", "json")...)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			var config ws.LastConfig
+			if err := json.Unmarshal([]byte(out), &config); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			tt.assert(t, config)
+		})
+	}
+}
+
+func TestSystemCmd_Flags(t *testing.T) {
+	server := wstest.NewServer(wstest.NewDevice("devices/home"))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"missing device", []string{"standby", "on"}, `required flag(s) "device-name" not set`},
+		{"missing heating/cooling mode", []string{"hc-mode", "set", "--device-name", "devices/home"}, "accepts 1 arg(s), received 0"},
+		{"unavailable heating/cooling mode", []string{"hc-mode", "set", "auto", "--device-name", "devices/home"}, ws.ErrHcModeNotAvailable.Error()},
+		{"missing standby mode", []string{"standby", "--device-name", "devices/home"}, "accepts 1 arg(s), received 0"},
+		{"invalid standby mode", []string{"standby", "maybe", "--device-name", "devices/home"}, `invalid argument "maybe"`},
+		{"missing quiet mode", []string{"quiet", "set", "--device-name", "devices/home"}, "accepts 1 arg(s), received 0"},
+		{"invalid quiet mode", []string{"quiet", "set", "loud", "--device-name", "devices/home"}, ws.ErrInvalidQuietMode.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			before := server.Requests("UpdateConfig")
+
+			// Act
+			_, err := runCommand(t, server, append([]string{"system"}, tt.args...)...)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+			if requests := server.Requests("UpdateConfig") - before; requests != 0 {
+				t.Errorf("Expected no updates, got %d", requests)
+			}
+		})
+	}
+}
+
+func TestSetQuietCmd(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(wstest.NewDevice("devices/home"))
+	defer server.Close()
+
+	// Act
+	out, err := runCommand(t, server, "system", "quiet", "set", "on", "--device-name", "devices/home", "--output", "json")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var settings ws.QuietSettings
+	if err := json.Unmarshal([]byte(out), &settings); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if settings.Mode != ws.QuietModeOn {
+		t.Errorf("Expected quiet mode on, got %s", settings.Mode)
+	}
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/zmoog/ws/v2/feedback"
+	"github.com/zmoog/ws/v2/ws"
+)
+
+var (
+	vacationUntil string
+)
+
+// vacationUntilLayouts are the layouts accepted by --until, in local
+// time unless the value has a time zone.
+var vacationUntilLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+// vacationCmd represents the vacation command
+var vacationCmd = &cobra.Command{
+	Use:   "vacation",
+	Short: "Manage the vacation mode",
+	Long:  `Turn the vacation mode of a device on and off.`,
+}
+
+// vacationOnCmd represents the vacation on command
+var vacationOnCmd = &cobra.Command{
+	Use:   "on",
+	Short: "Turn on the vacation mode",
+	Long: `Turn on the vacation mode of a device until the given date.
+
+The end of the vacation must be in the future, e.g. 2026-12-28T18:00 in
+local time, or an RFC 3339 timestamp with a time zone.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		until, err := parseVacationUntil(vacationUntil)
+		if err != nil {
+			return err
+		}
+
+		client := newClient()
+
+		device, err := client.StartVacationContext(cmd.Context(), ulc, until)
+		if err != nil {
+			return fmt.Errorf("failed to turn on vacation mode: %w", err)
+		}
+
+		return feedback.PrintResult(vacationResult{settings: device.LastConfig.Sentio.VacationSettings})
+	},
+}
+
+// vacationOffCmd represents the vacation off command
+var vacationOffCmd = &cobra.Command{
+	Use:   "off",
+	Short: "Turn off the vacation mode",
+	Long:  `Turn off the vacation mode of a device.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+
+		device, err := client.StopVacationContext(cmd.Context(), ulc)
+		if err != nil {
+			return fmt.Errorf("failed to turn off vacation mode: %w", err)
+		}
+
+		return feedback.PrintResult(vacationResult{settings: device.LastConfig.Sentio.VacationSettings})
+	},
+}
+
+// parseVacationUntil parses the end of a vacation using the first
+// matching layout.
+func parseVacationUntil(value string) (time.Time, error) {
+	for _, layout := range vacationUntilLayouts {
+		if until, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return until, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid vacation end %q, use a date like 2026-12-28T18:00", value)
+}
+
+type vacationResult struct {
+	settings ws.VacationSettings
+}
+
+func (r vacationResult) Table() string {
+	table := pterm.TableData{
+		{"Vacation mode", "Until"},
+		{r.settings.VacationMode.String(), formatVacationUntil(r.settings.VacationModeUntil)},
+	}
+
+	rendered, err := pterm.DefaultTable.WithHasHeader().WithData(table).Srender()
+	if err != nil {
+		return fmt.Sprintf("failed to render table: %s", err)
+	}
+
+	return rendered + "\n"
+}
+
+func formatVacationUntil(until time.Time) string {
+	if until.IsZero() {
+		return "-"
+	}
+
+	return until.Local().Format("2006-01-02 15:04")
+}
+
+func (r vacationResult) String() string {
+	return r.Table()
+}
+
+func (r vacationResult) Data() any {
+	return r.settings
+}
+
+func init() {
+	rootCmd.AddCommand(vacationCmd)
+
+	vacationCmd.AddCommand(vacationOnCmd)
+	vacationCmd.AddCommand(vacationOffCmd)
+
+	vacationOnCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	vacationOnCmd.Flags().StringVar(&vacationUntil, "until", "", "End of the vacation (e.g. 2026-12-28T18:00)")
+	_ = vacationOnCmd.MarkFlagRequired("device-name")
+	_ = vacationOnCmd.MarkFlagRequired("until")
+
+	vacationOffCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	_ = vacationOffCmd.MarkFlagRequired("device-name")
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zmoog/ws/v2/ws"
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+func TestVacationOnCmd(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(wstest.NewDevice("devices/home"))
+	defer server.Close()
+
+	until := time.Now().Add(48 * time.Hour).Truncate(time.Minute)
+
+	// Act
+	out, err := runCommand(t, server, "vacation", "on", "--device-name", "devices/home", "--until", until.Format("2006-01-02T15:04"), "--output", "json")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var settings ws.VacationSettings
+	if err := json.Unmarshal([]byte(out), &settings); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if settings.VacationMode != ws.VacationModeOn || !settings.VacationModeUntil.Equal(until) {
+		t.Errorf("Expected vacation mode on until %s, got %+v", until, settings)
+	}
+}
+
+func TestVacationCmd_Flags(t *testing.T) {
+	server := wstest.NewServer(wstest.NewDevice("devices/home"))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"missing device", []string{"off"}, `required flag(s) "device-name" not set`},
+		{"missing end", []string{"on", "--device-name", "devices/home"}, `required flag(s) "until" not set`},
+		{"invalid end", []string{"on", "--device-name", "devices/home", "--until", "next week"}, `invalid vacation end "next week"`},
+		{"end in the past", []string{"on", "--device-name", "devices/home", "--until", "2020-01-01"}, ws.ErrVacationEnd.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			before := server.Requests("UpdateConfig")
+
+			// Act
+			_, err := runCommand(t, server, append([]string{"vacation"}, tt.args...)...)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+			if requests := server.Requests("UpdateConfig") - before; requests != 0 {
+				t.Errorf("Expected no updates, got %d", requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/zmoog/ws/v2/feedback"
+	"github.com/zmoog/ws/v2/ws"
+)
+
+var (
+	watchInterval time.Duration
+)
+
+// watchRoomsCmd represents the watch command
+var watchRoomsCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch the rooms",
+	Long: `Watch the rooms in a location, polling the device at regular intervals.
+
+In table mode, the table is redrawn in place and the values changed since
+the previous poll are highlighted. In JSON and NDJSON modes, one JSON object
+is printed per line for each room that changed. The other output formats
+are not available.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchInterval <= 0 {
+			return fmt.Errorf("invalid interval: %s", watchInterval)
+		}
+
+		var w roomsWatcher
+		switch format := feedback.Format(); format {
+		case feedback.JSON, feedback.NDJSON:
+			w = &jsonRoomsWatcher{}
+		case feedback.Table, feedback.Text:
+			area, err := pterm.DefaultArea.Start()
+			if err != nil {
+				return fmt.Errorf("failed to start watch area: %w", err)
+			}
+			defer area.Stop() // nolint
+
+			w = &tableRoomsWatcher{area: area}
+		default:
+			return fmt.Errorf("%w: %s is not available for this command", feedback.ErrUnsupportedFormat, format)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		client := newClient()
+
+		return watchRooms(ctx, client, ulc, watchInterval, w)
+	},
+}
+
+// roomsWatcher renders the changes detected while watching the rooms.
+type roomsWatcher interface {
+	// Update is called after each successful poll with the current
+	// device and the rooms from the previous poll, keyed by ID.
+	Update(device ws.Device, previous map[string]ws.Room, now time.Time)
+	// Error is called when a poll fails.
+	Error(err error, now time.Time)
+}
+
+// watchRooms polls the device until ctx is done. It returns nil when
+// interrupted, and the context error when the deadline is exceeded.
+func watchRooms(ctx context.Context, client *ws.Client, deviceName string, interval time.Duration, w roomsWatcher) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous map[string]ws.Room
+	for {
+		device, err := client.GetDeviceContext(ctx, deviceName)
+		switch {
+		case ctx.Err() != nil:
+			return watchStopped(ctx)
+		case err != nil:
+			w.Error(err, time.Now())
+		default:
+			w.Update(device, previous, time.Now())
+
+			previous = make(map[string]ws.Room)
+			for _, room := range device.LastConfig.Sentio.Rooms {
+				previous[room.ID] = room
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return watchStopped(ctx)
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchStopped returns the error of a watch stopped by ctx: nil when it
+// was interrupted, e.g. with Ctrl+C, and the context error when the
+// --timeout deadline was exceeded, so it is reported as a timeout.
+func watchStopped(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// Fields reported as changed by roomChanges.
+const (
+	fieldAirTemperature   = "airTemperature"
+	fieldHumidity         = "humidity"
+	fieldTemperatureState = "temperatureState"
+)
+
+// roomChanges returns the watched fields that differ between the
+// previous and the current state of a room. All the fields are
+// returned when the room has no previous state.
+func roomChanges(previous ws.Room, seen bool, current ws.Room) []string {
+	if !seen {
+		return []string{fieldAirTemperature, fieldHumidity, fieldTemperatureState}
+	}
+
+	var changes []string
+	if previous.AirTemperature != current.AirTemperature {
+		changes = append(changes, fieldAirTemperature)
+	}
+	if previous.Humidity != current.Humidity {
+		changes = append(changes, fieldHumidity)
+	}
+	if previous.TemperatureState != current.TemperatureState {
+		changes = append(changes, fieldTemperatureState)
+	}
+
+	return changes
+}
+
+// tableRoomsWatcher redraws the rooms table in place.
+type tableRoomsWatcher struct {
+	area    *pterm.AreaPrinter
+	device  ws.Device
+	changes map[string][]string
+	updated time.Time
+}
+
+// highlightedColumns maps the watched fields to the columns
+// of the rooms table.
+var highlightedColumns = map[string]int{
+	fieldTemperatureState: 1,
+	fieldAirTemperature:   3,
+	fieldHumidity:         4,
+}
+
+func (w *tableRoomsWatcher) Update(device ws.Device, previous map[string]ws.Room, now time.Time) {
+	w.device = device
+	w.updated = now
+	w.changes = make(map[string][]string)
+
+	// Nothing is highlighted on the first poll.
+	if previous != nil {
+		for _, room := range device.LastConfig.Sentio.Rooms {
+			prev, seen := previous[room.ID]
+			w.changes[room.ID] = roomChanges(prev, seen, room)
+		}
+	}
+
+	w.render("")
+}
+
+func (w *tableRoomsWatcher) Error(err error, now time.Time) {
+	w.render(pterm.Error.Sprintf("%s: failed to get device: %s", now.Format(time.TimeOnly), err))
+}
+
+func (w *tableRoomsWatcher) render(message string) {
+	table := pterm.TableData{roomsTableHeader()}
+	for _, room := range w.device.LastConfig.Sentio.Rooms {
+		row := roomsTableRow(room, w.device.LastConfig.Sentio.HcMode)
+		for _, field := range w.changes[room.ID] {
+			column := highlightedColumns[field]
+			row[column] = pterm.FgLightYellow.Sprint(row[column])
+		}
+		table = append(table, row)
+	}
+
+	var sb strings.Builder
+
+	rendered, err := pterm.DefaultTable.WithHasHeader().WithData(table).Srender()
+	if err != nil {
+		rendered = fmt.Sprintf("failed to render table: %s", err)
+	}
+	sb.WriteString(rendered)
+	sb.WriteString("\n\n")
+
+	for _, sensor := range w.device.LastConfig.Sentio.OutdoorTemperatureSensors {
+		sb.WriteString(fmt.Sprintf("Outdoor temperature: %.1f\n", sensor.OutdoorTemperature))
+	}
+	if !w.updated.IsZero() {
+		sb.WriteString(fmt.Sprintf("Last update: %s (every %s, press Ctrl+C to stop)\n", w.updated.Format(time.TimeOnly), watchInterval))
+	}
+	if message != "" {
+		sb.WriteString(message)
+	}
+
+	w.area.Update(sb.String())
+}
+
+// roomChangeEvent is the JSON event emitted when a room changes.
+type roomChangeEvent struct {
+	Time    time.Time `json:"time"`
+	Device  string    `json:"device"`
+	Changed []string  `json:"changed"`
+	Room    ws.Room   `json:"room"`
+}
+
+// jsonRoomsWatcher prints one JSON event per line for each room change.
+type jsonRoomsWatcher struct{}
+
+func (w *jsonRoomsWatcher) Update(device ws.Device, previous map[string]ws.Room, now time.Time) {
+	for _, room := range device.LastConfig.Sentio.Rooms {
+		prev, seen := previous[room.ID]
+
+		changes := roomChanges(prev, seen, room)
+		if len(changes) == 0 {
+			continue
+		}
+
+		event, err := json.Marshal(roomChangeEvent{
+			Time:    now.UTC(),
+			Device:  device.Name,
+			Changed: changes,
+			Room:    room,
+		})
+		if err != nil {
+			feedback.Error(fmt.Sprintf("failed to marshal event: %s", err))
+			continue
+		}
+
+		feedback.Println(string(event))
+	}
+}
+
+func (w *jsonRoomsWatcher) Error(err error, now time.Time) {
+	feedback.Error(fmt.Sprintf("%s: failed to get device: %s", now.Format(time.RFC3339), err))
+}
+
+func init() {
+	roomsCmd.AddCommand(watchRoomsCmd)
+
+	watchRoomsCmd.Flags().StringVarP(&ulc, "device-name", "d", "", "Device name")
+	watchRoomsCmd.Flags().DurationVarP(&watchInterval, "interval", "i", 30*time.Second, "Polling interval")
+	_ = watchRoomsCmd.MarkFlagRequired("device-name")
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zmoog/ws/v2/feedback"
+	"github.com/zmoog/ws/v2/ws"
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+func TestRoomChanges(t *testing.T) {
+	room := wstest.NewRoom("room-1", "Kitchen")
+
+	warmer := room
+	warmer.AirTemperature = 21
+
+	heating := room
+	heating.TemperatureState = ws.TemperatureStateHeating
+	heating.Humidity = 50
+
+	tests := []struct {
+		name     string
+		previous ws.Room
+		seen     bool
+		current  ws.Room
+		expected []string
+	}{
+		{"first poll", ws.Room{}, false, room, []string{fieldAirTemperature, fieldHumidity, fieldTemperatureState}},
+		{"unchanged", room, true, room, nil},
+		{"air temperature", room, true, warmer, []string{fieldAirTemperature}},
+		{"humidity and state", room, true, heating, []string{fieldHumidity, fieldTemperatureState}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			changes := roomChanges(tt.previous, tt.seen, tt.current)
+
+			// Assert
+			if !slices.Equal(changes, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, changes)
+			}
+		})
+	}
+}
+
+func TestJSONRoomsWatcher_Update(t *testing.T) {
+	kitchen := wstest.NewRoom("room-1", "Kitchen")
+	bedroom := wstest.NewRoom("room-2", "Bedroom")
+
+	warmer := kitchen
+	warmer.AirTemperature = 22
+
+	tests := []struct {
+		name     string
+		previous map[string]ws.Room
+		rooms    []ws.Room
+		expected []string
+	}{
+		{"first poll", nil, []ws.Room{kitchen, bedroom}, []string{"room-1", "room-2"}},
+		{"no changes", map[string]ws.Room{"room-1": kitchen, "room-2": bedroom}, []ws.Room{kitchen, bedroom}, nil},
+		{"one room changed", map[string]ws.Room{"room-1": kitchen, "room-2": bedroom}, []ws.Room{warmer, bedroom}, []string{"room-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var out bytes.Buffer
+			feedback.SetDefault(feedback.New(&out, &bytes.Buffer{}, feedback.JSON))
+			defer feedback.SetDefault(feedback.Default())
+
+			device := wstest.NewDevice("devices/home", tt.rooms...)
+			now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+			// Act
+			(&jsonRoomsWatcher{}).Update(device, tt.previous, now)
+
+			// Assert
+			var ids []string
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				if line == "" {
+					continue
+				}
+				var event roomChangeEvent
+				if err := json.Unmarshal([]byte(line), &event); err != nil {
+					t.Fatalf("Expected one JSON event per line, got %q: %v", line, err)
+				}
+				if event.Device != "devices/home" || !event.Time.Equal(now) {
+					t.Errorf("Expected an event for devices/home at %s, got %s at %s", now, event.Device, event.Time)
+				}
+				ids = append(ids, event.Room.ID)
+			}
+			if !slices.Equal(ids, tt.expected) {
+				t.Errorf("Expected events for %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+type discardWatcher struct{}
+
+func (discardWatcher) Update(ws.Device, map[string]ws.Room, time.Time) {}
+func (discardWatcher) Error(error, time.Time)                          {}
+
+func TestWatchRooms_Stopped(t *testing.T) {
+	server := wstest.NewServer(wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		stop     func() (context.Context, context.CancelFunc)
+		expected error
+	}{
+		{"interrupted", func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+			return ctx, cancel
+		}, nil},
+		{"timed out", func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 50*time.Millisecond)
+		}, context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx, cancel := tt.stop()
+			defer cancel()
+
+			// Act
+			err := watchRooms(ctx, server.Client(), "devices/home", 10*time.Millisecond, discardWatcher{})
+
+			// Assert
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if tt.expected != nil && exitCode(err) != exitCodeTimeout {
+				t.Errorf("Expected exit code %d, got %d", exitCodeTimeout, exitCode(err))
+			}
+		})
+	}
+}
+
+func TestWatchRoomsCmd_UnsupportedFormats(t *testing.T) {
+	server := wstest.NewServer(wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")))
+	defer server.Close()
+
+	for _, format := range []string{"yaml", "csv", "tsv", "template={{.}}", "jsonpath={.id}"} {
+		t.Run(format, func(t *testing.T) {
+			// Arrange
+			before := server.Requests("GetDevice")
+
+			// Act
+			_, err := runCommand(t, server, "rooms", "watch", "--device-name", "devices/home", "--output", format)
+
+			// Assert
+			if !errors.Is(err, feedback.ErrUnsupportedFormat) {
+				t.Fatalf("Expected %v, got %v", feedback.ErrUnsupportedFormat, err)
+			}
+			if requests := server.Requests("GetDevice") - before; requests != 0 {
+				t.Errorf("Expected no requests, got %d", requests)
+			}
+		})
+	}
+}
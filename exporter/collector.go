@@ -0,0 +1,226 @@
+// Package exporter exposes the state of Wavin Sentio devices
+// as Prometheus metrics.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/zmoog/ws/v2/ws"
+)
+
+const namespace = "ws"
+
+var (
+	roomLabels = []string{"device", "room_id", "room"}
+
+	upDesc = prometheus.NewDesc(
+		namespace+"_up",
+		"Whether the last poll of the Wavin Sentio API was successful.",
+		nil, nil,
+	)
+	lastPollDesc = prometheus.NewDesc(
+		namespace+"_last_poll_timestamp_seconds",
+		"Unix time of the last poll of the Wavin Sentio API.",
+		nil, nil,
+	)
+	pollErrorsDesc = prometheus.NewDesc(
+		namespace+"_poll_errors_total",
+		"Number of failed polls of the Wavin Sentio API.",
+		nil, nil,
+	)
+	heartbeatAgeDesc = prometheus.NewDesc(
+		namespace+"_device_heartbeat_age_seconds",
+		"Seconds since the last heartbeat of the device.",
+		[]string{"device"}, nil,
+	)
+	firmwareUpdateDesc = prometheus.NewDesc(
+		namespace+"_device_firmware_update_available",
+		"Whether a firmware update is available for the device.",
+		[]string{"device", "installed", "available"}, nil,
+	)
+	outdoorTemperatureDesc = prometheus.NewDesc(
+		namespace+"_outdoor_temperature_celsius",
+		"Outdoor temperature measured by the sensor.",
+		[]string{"device", "sensor_id"}, nil,
+	)
+	airTemperatureDesc = prometheus.NewDesc(
+		namespace+"_room_air_temperature_celsius",
+		"Current air temperature of the room.",
+		roomLabels, nil,
+	)
+	humidityDesc = prometheus.NewDesc(
+		namespace+"_room_humidity_percent",
+		"Current relative humidity of the room.",
+		roomLabels, nil,
+	)
+	setpointDesc = prometheus.NewDesc(
+		namespace+"_room_setpoint_temperature_celsius",
+		"Desired temperature of the room.",
+		roomLabels, nil,
+	)
+	temperatureStateDesc = prometheus.NewDesc(
+		namespace+"_room_temperature_state",
+		"Temperature state of the room, 1 for the current state.",
+		append(roomLabels, "state"), nil,
+	)
+	dehumidifierStateDesc = prometheus.NewDesc(
+		namespace+"_room_dehumidifier_state",
+		"Dehumidifier state of the room, 1 for the current state.",
+		append(roomLabels, "state"), nil,
+	)
+)
+
+// Collector is a Prometheus collector for the devices in a Wavin
+// Sentio account.
+//
+// The devices are fetched by Poll, usually called at regular intervals
+// by Run, and the metrics are collected from the last successful poll.
+type Collector struct {
+	client *ws.Client
+	now    func() time.Time
+
+	mu         sync.RWMutex
+	devices    []ws.Device
+	up         bool
+	lastPoll   time.Time
+	pollErrors int
+}
+
+// NewCollector creates a collector for the devices returned by client.
+func NewCollector(client *ws.Client) *Collector {
+	return &Collector{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// Run polls the devices every interval until ctx is done.
+// The errors are reported by the up and poll errors metrics.
+func (c *Collector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = c.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetches the current state of all the devices in the account.
+//
+// If the poll fails, the device metrics are dropped until the next
+// successful poll, so stale values are never exported.
+func (c *Collector) Poll(ctx context.Context) error {
+	devices, err := c.fetch(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastPoll = c.now()
+	c.up = err == nil
+	c.devices = devices
+	if err != nil {
+		c.pollErrors++
+	}
+
+	return err
+}
+
+func (c *Collector) fetch(ctx context.Context) ([]ws.Device, error) {
+	list, err := c.client.ListDevicesContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	devices := make([]ws.Device, 0, len(list))
+	for _, d := range list {
+		device, err := c.client.GetDeviceContext(ctx, d.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get device %s: %w", d.Name, err)
+		}
+		devices = append(devices, device)
+	}
+
+	return devices, nil
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- upDesc
+	ch <- lastPollDesc
+	ch <- pollErrorsDesc
+	ch <- heartbeatAgeDesc
+	ch <- firmwareUpdateDesc
+	ch <- outdoorTemperatureDesc
+	ch <- airTemperatureDesc
+	ch <- humidityDesc
+	ch <- setpointDesc
+	ch <- temperatureStateDesc
+	ch <- dehumidifierStateDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, boolToFloat(c.up))
+	ch <- prometheus.MustNewConstMetric(pollErrorsDesc, prometheus.CounterValue, float64(c.pollErrors))
+	if !c.lastPoll.IsZero() {
+		ch <- prometheus.MustNewConstMetric(lastPollDesc, prometheus.GaugeValue, float64(c.lastPoll.Unix()))
+	}
+
+	now := c.now()
+	for _, device := range c.devices {
+		c.collectDevice(ch, device, now)
+	}
+}
+
+func (c *Collector) collectDevice(ch chan<- prometheus.Metric, device ws.Device, now time.Time) {
+	if !device.LastHeartbeat.IsZero() {
+		ch <- prometheus.MustNewConstMetric(heartbeatAgeDesc, prometheus.GaugeValue,
+			now.Sub(device.LastHeartbeat).Seconds(), device.Name)
+	}
+
+	ch <- prometheus.MustNewConstMetric(firmwareUpdateDesc, prometheus.GaugeValue,
+		boolToFloat(device.FirmwareAvailable != "" && device.FirmwareAvailable != device.FirmwareInstalled),
+		device.Name, device.FirmwareInstalled, device.FirmwareAvailable)
+
+	for _, sensor := range device.LastConfig.Sentio.OutdoorTemperatureSensors {
+		ch <- prometheus.MustNewConstMetric(outdoorTemperatureDesc, prometheus.GaugeValue,
+			sensor.OutdoorTemperature, device.Name, sensor.ID)
+	}
+
+	for _, room := range device.LastConfig.Sentio.Rooms {
+		labels := []string{device.Name, room.ID, room.Title}
+
+		ch <- prometheus.MustNewConstMetric(airTemperatureDesc, prometheus.GaugeValue, room.AirTemperature, labels...)
+		ch <- prometheus.MustNewConstMetric(humidityDesc, prometheus.GaugeValue, room.Humidity, labels...)
+		ch <- prometheus.MustNewConstMetric(setpointDesc, prometheus.GaugeValue, room.SetpointTemperature, labels...)
+
+		if room.TemperatureState != "" {
+			ch <- prometheus.MustNewConstMetric(temperatureStateDesc, prometheus.GaugeValue, 1,
+				append(labels, string(room.TemperatureState))...)
+		}
+		if room.DehumidifierState != "" {
+			ch <- prometheus.MustNewConstMetric(dehumidifierStateDesc, prometheus.GaugeValue, 1,
+				append(labels, string(room.DehumidifierState))...)
+		}
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
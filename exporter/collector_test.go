@@ -0,0 +1,80 @@
+package exporter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/zmoog/ws/v2/ws"
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+func TestCollector_Poll(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(
+		wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")),
+	)
+	defer server.Close()
+
+	collector := NewCollector(server.Client())
+
+	// Act
+	err := collector.Poll(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `
+# HELP ws_up Whether the last poll of the Wavin Sentio API was successful.
+# TYPE ws_up gauge
+ws_up 1
+# HELP ws_room_setpoint_temperature_celsius Desired temperature of the room.
+# TYPE ws_room_setpoint_temperature_celsius gauge
+ws_room_setpoint_temperature_celsius{device="devices/home",room="Kitchen",room_id="room-1"} 21
+# HELP ws_outdoor_temperature_celsius Outdoor temperature measured by the sensor.
+# TYPE ws_outdoor_temperature_celsius gauge
+ws_outdoor_temperature_celsius{device="devices/home",sensor_id="outdoor-1"} 8.5
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"ws_up", "ws_room_setpoint_temperature_celsius", "ws_outdoor_temperature_celsius"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollector_PollError(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(
+		wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")),
+	)
+	defer server.Close()
+
+	collector := NewCollector(server.Client(ws.WithMaxRetries(0)))
+	if err := collector.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	server.FailNext("ListDevices", ws.CodeUnavailable, "maintenance")
+
+	// Act
+	err := collector.Poll(context.Background())
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+
+	expected := `
+# HELP ws_up Whether the last poll of the Wavin Sentio API was successful.
+# TYPE ws_up gauge
+ws_up 0
+# HELP ws_poll_errors_total Number of failed polls of the Wavin Sentio API.
+# TYPE ws_poll_errors_total counter
+ws_poll_errors_total 1
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"ws_up", "ws_poll_errors_total", "ws_room_air_temperature_celsius"); err != nil {
+		t.Error(err)
+	}
+}
@@ -12,6 +12,14 @@ func SetFormat(format OutputFormat) {
 	fb.SetFormat(format)
 }
 
+func SetOutput(value string) error {
+	return fb.SetOutput(value)
+}
+
+func Format() OutputFormat {
+	return fb.Format()
+}
+
 func Println(v interface{}) {
 	fb.Println(v)
 }
@@ -1,24 +1,78 @@
 package feedback
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
 )
 
 const (
 	Text OutputFormat = iota
 	JSON
 	Table
+	CSV
+	TSV
+	YAML
+	NDJSON
+	Template
+	JSONPath
 )
 
 type OutputFormat int
 
+// ErrUnsupportedFormat is returned when a result cannot be printed in
+// the output format, e.g. a result without rows as CSV.
+var ErrUnsupportedFormat = errors.New("unsupported output format")
+
+var formatNames = map[OutputFormat]string{
+	Text:     "text",
+	JSON:     "json",
+	Table:    "table",
+	CSV:      "csv",
+	TSV:      "tsv",
+	YAML:     "yaml",
+	NDJSON:   "ndjson",
+	Template: "template",
+	JSONPath: "jsonpath",
+}
+
+// ErrInvalidExpression is returned when the template of the Template
+// or JSONPath format cannot be parsed.
+var ErrInvalidExpression = errors.New("invalid output expression")
+
+func (f OutputFormat) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("OutputFormat(%d)", int(f))
+}
+
+// ParseOutputFormat returns the output format with the given name,
+// e.g. "json".
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	for format, n := range formatNames {
+		if n == name {
+			return format, nil
+		}
+	}
+
+	return Table, fmt.Errorf("%w: %s", ErrUnsupportedFormat, name)
+}
+
 type Feedback struct {
-	out    io.Writer
-	err    io.Writer
-	format OutputFormat
+	out      io.Writer
+	err      io.Writer
+	format   OutputFormat
+	template *template.Template
+	jsonPath *jsonPath
 }
 
 func New(out, err io.Writer, format OutputFormat) *Feedback {
@@ -33,6 +87,54 @@ func (fb *Feedback) SetFormat(format OutputFormat) {
 	fb.format = format
 }
 
+// SetOutput sets the format from an output flag value: a format name,
+// e.g. "json", or a format with its template, e.g. "template={{.Title}}"
+// or "jsonpath={.[*].title}".
+//
+// The template is parsed immediately, so an invalid one is reported
+// before running the command.
+func (fb *Feedback) SetOutput(value string) error {
+	name, text, hasText := strings.Cut(value, "=")
+
+	format, err := ParseOutputFormat(name)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case Template:
+		if !hasText {
+			return fmt.Errorf("%w: use template=TEMPLATE, e.g. template='{{range .}}{{.Title}}{{\"\\n\"}}{{end}}'", ErrInvalidExpression)
+		}
+		tmpl, err := template.New("output").Option("missingkey=error").Parse(text)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidExpression, err)
+		}
+		fb.template = tmpl
+	case JSONPath:
+		if !hasText {
+			return fmt.Errorf("%w: use jsonpath=TEMPLATE, e.g. jsonpath='{.[*].title}'", ErrInvalidExpression)
+		}
+		path, err := parseJSONPath(text)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidExpression, err)
+		}
+		fb.jsonPath = path
+	default:
+		if hasText {
+			return fmt.Errorf("%w: %s", ErrUnsupportedFormat, value)
+		}
+	}
+
+	fb.format = format
+
+	return nil
+}
+
+func (fb *Feedback) Format() OutputFormat {
+	return fb.format
+}
+
 func (fb *Feedback) Println(v interface{}) {
 	_, _ = fmt.Fprintln(fb.out, v)
 }
@@ -53,6 +155,39 @@ func (fb *Feedback) PrintResult(result Result) error {
 		output = string(byteOutput)
 	case Table:
 		output = result.Table()
+	case YAML:
+		byteOutput, err := marshalYAML(result.Data())
+		if err != nil {
+			return fmt.Errorf("failed to marshall result: %w", err)
+		}
+		output = string(byteOutput)
+	case NDJSON:
+		byteOutput, err := marshalNDJSON(result.Data())
+		if err != nil {
+			return fmt.Errorf("failed to marshall result: %w", err)
+		}
+		output = string(byteOutput)
+	case Template:
+		var buf bytes.Buffer
+		if err := fb.template.Execute(&buf, result.Data()); err != nil {
+			return fmt.Errorf("failed to execute template: %w", err)
+		}
+		output = buf.String()
+	case JSONPath:
+		var err error
+		output, err = fb.jsonPath.execute(result.Data())
+		if err != nil {
+			return err
+		}
+		if !strings.HasSuffix(output, "\n") {
+			output += "\n"
+		}
+	case CSV, TSV:
+		tabular, ok := result.(TabularResult)
+		if !ok {
+			return fmt.Errorf("%w: %s is not available for this command", ErrUnsupportedFormat, fb.format)
+		}
+		return fb.printRows(tabular)
 	default:
 		output = result.String()
 	}
@@ -65,8 +200,97 @@ func (fb *Feedback) PrintResult(result Result) error {
 	return nil
 }
 
+// marshalYAML returns the YAML encoding of v, with the same keys and
+// values as its JSON encoding.
+//
+// Since JSON is valid YAML, the JSON encoding is decoded as a YAML node
+// tree, keeping the order of the keys, and encoded back in block style.
+func marshalYAML(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	resetStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// resetStyle clears the flow and quoting styles of the JSON encoding,
+// so the nodes are encoded in the default YAML style.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// marshalNDJSON returns the JSON encoding of v on a single line, or of
+// each element on its own line if v is encoded as a JSON array.
+func marshalNDJSON(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		// Not an array, a single line.
+		return append(data, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+	for _, element := range elements {
+		buf.Write(element)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// printRows prints the rows of a result as comma or tab separated values.
+func (fb *Feedback) printRows(result TabularResult) error {
+	w := csv.NewWriter(fb.out)
+	if fb.format == TSV {
+		w.Comma = '\t'
+	}
+
+	header, records := result.Rows()
+	_ = w.Write(header)
+	_ = w.WriteAll(records)
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to print result: %w", err)
+	}
+
+	return nil
+}
+
 type Result interface {
 	fmt.Stringer
 	Data() interface{}
 	Table() string
 }
+
+// TabularResult is a result that can be printed as rows, e.g. as CSV.
+//
+// The records hold the raw values, like the API values of the states
+// and RFC 3339 timestamps, so they can be parsed back.
+type TabularResult interface {
+	Result
+	// Rows returns the header and the records of the result.
+	Rows() (header []string, records [][]string)
+}
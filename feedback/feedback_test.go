@@ -0,0 +1,186 @@
+package feedback
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type rowsResult struct{}
+
+func (r rowsResult) String() string { return "text" }
+func (r rowsResult) Data() any      { return []string{"a", "b"} }
+func (r rowsResult) Table() string  { return "table" }
+
+func (r rowsResult) Rows() ([]string, [][]string) {
+	return []string{"Name", "Value"}, [][]string{{"Kitchen", "20.5"}, {"Living, Room", "21"}}
+}
+
+type textResult struct{}
+
+func (r textResult) String() string { return "text" }
+func (r textResult) Data() any      { return "text" }
+func (r textResult) Table() string  { return "table" }
+
+func TestFeedback_PrintResultRows(t *testing.T) {
+	tests := []struct {
+		format   OutputFormat
+		expected string
+	}{
+		{CSV, "Name,Value\nKitchen,20.5\n\"Living, Room\",21\n"},
+		{TSV, "Name\tValue\nKitchen\t20.5\nLiving, Room\t21\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			// Arrange
+			var out bytes.Buffer
+			fb := New(&out, &bytes.Buffer{}, tt.format)
+
+			// Act
+			err := fb.PrintResult(rowsResult{})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestFeedback_PrintResultNotTabular(t *testing.T) {
+	// Arrange
+	fb := New(&bytes.Buffer{}, &bytes.Buffer{}, CSV)
+
+	// Act
+	err := fb.PrintResult(textResult{})
+
+	// Assert
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("Expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+type room struct {
+	Title          string  `json:"title"`
+	AirTemperature float64 `json:"airTemperature"`
+}
+
+type roomsResult struct {
+	rooms any
+}
+
+func (r roomsResult) String() string { return "text" }
+func (r roomsResult) Data() any      { return r.rooms }
+func (r roomsResult) Table() string  { return "table" }
+
+func TestFeedback_PrintResultData(t *testing.T) {
+	rooms := []room{{Title: "Kitchen", AirTemperature: 20.5}, {Title: "Bedroom", AirTemperature: 18}}
+
+	tests := []struct {
+		name     string
+		format   OutputFormat
+		data     any
+		expected string
+	}{
+		{"yaml", YAML, rooms, "- title: Kitchen\n  airTemperature: 20.5\n- title: Bedroom\n  airTemperature: 18\n"},
+		{"ndjson", NDJSON, rooms, "{\"title\":\"Kitchen\",\"airTemperature\":20.5}\n{\"title\":\"Bedroom\",\"airTemperature\":18}\n"},
+		{"ndjson object", NDJSON, rooms[0], "{\"title\":\"Kitchen\",\"airTemperature\":20.5}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var out bytes.Buffer
+			fb := New(&out, &bytes.Buffer{}, tt.format)
+
+			// Act
+			err := fb.PrintResult(roomsResult{rooms: tt.data})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestFeedback_SetOutputTemplate(t *testing.T) {
+	// Arrange
+	var out bytes.Buffer
+	fb := New(&out, &bytes.Buffer{}, Table)
+	rooms := []room{{Title: "Kitchen", AirTemperature: 20.5}, {Title: "Bedroom", AirTemperature: 18}}
+
+	// Act
+	err := fb.SetOutput(`template={{range .}}{{.Title}}={{.AirTemperature}}{{"\n"}}{{end}}`)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	err = fb.PrintResult(roomsResult{rooms: rooms})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expected := "Kitchen=20.5\nBedroom=18\n"; out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestFeedback_SetOutputErrors(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected error
+	}{
+		{"xml", ErrUnsupportedFormat},
+		{"json=x", ErrUnsupportedFormat},
+		{"template", ErrInvalidExpression},
+		{"template={{range .}", ErrInvalidExpression},
+		{"jsonpath={.title", ErrInvalidExpression},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// Arrange
+			fb := New(&bytes.Buffer{}, &bytes.Buffer{}, Table)
+
+			// Act
+			err := fb.SetOutput(tt.value)
+
+			// Assert
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, err)
+			}
+			if fb.Format() != Table {
+				t.Errorf("Expected the format to be unchanged, got %s", fb.Format())
+			}
+		})
+	}
+}
+
+func TestFeedback_PrintResultTemplateError(t *testing.T) {
+	// Arrange
+	var out bytes.Buffer
+	fb := New(&out, &bytes.Buffer{}, Table)
+	if err := fb.SetOutput("template={{.Humidity}}"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Act
+	err := fb.PrintResult(roomsResult{rooms: room{Title: "Kitchen"}})
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "can't evaluate field Humidity") {
+		t.Fatalf("Expected an execution error, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no output, got %q", out.String())
+	}
+}
@@ -0,0 +1,342 @@
+package feedback
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a small subset of the kubectl JSONPath syntax, applied to
+// the JSON encoding of a result.
+//
+// The expressions are enclosed in braces, and the text outside them is
+// printed as is, e.g. "{.[*].title}" or "Outdoor: {.outdoorTemperature}".
+// An expression is a path starting with "." or "$", made of fields
+// (".title" or "['title']"), indexes ("[0]" or "[-1]") and wildcards
+// (".*" or "[*]"). The values matched by an expression are separated by
+// spaces.
+//
+// Filters ("[?(...)]"), slices ("[0:2]"), unions ("[0,1]"), recursive
+// descent ("..") and range/end are not supported, and are rejected when
+// parsing the template.
+type jsonPath struct {
+	source string
+	parts  []pathPart
+}
+
+// pathPart is either a literal text or an expression.
+type pathPart struct {
+	text  string
+	steps []pathStep
+	expr  bool
+}
+
+type pathStepKind int
+
+const (
+	fieldStep pathStepKind = iota
+	indexStep
+	wildcardStep
+)
+
+type pathStep struct {
+	kind  pathStepKind
+	field string
+	index int
+}
+
+func (s pathStep) String() string {
+	switch s.kind {
+	case fieldStep:
+		return "." + s.field
+	case indexStep:
+		return fmt.Sprintf("[%d]", s.index)
+	default:
+		return "[*]"
+	}
+}
+
+// parseJSONPath parses a JSONPath template.
+func parseJSONPath(source string) (*jsonPath, error) {
+	p := &jsonPath{source: source}
+
+	rest := source
+	offset := 0
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			p.parts = append(p.parts, pathPart{text: rest})
+			break
+		}
+		if start > 0 {
+			p.parts = append(p.parts, pathPart{text: rest[:start]})
+		}
+
+		end := indexUnquoted(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("invalid JSONPath %q: unclosed { at position %d", source, offset+start)
+		}
+		end += start
+
+		steps, err := parsePathSteps(rest[start+1 : end])
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSONPath %q: %w at position %d", source, err, offset+start+1)
+		}
+		p.parts = append(p.parts, pathPart{steps: steps, expr: true})
+
+		offset += end + 1
+		rest = rest[end+1:]
+	}
+
+	return p, nil
+}
+
+// indexUnquoted returns the index of the first c in s outside single or
+// double quotes, or -1 if there is none.
+func indexUnquoted(s string, c byte) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '\'' || s[i] == '"':
+			quote = s[i]
+		case s[i] == c:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// errUnsupported returns the error for a JSONPath feature out of the
+// supported subset.
+func errUnsupported(feature string) error {
+	return fmt.Errorf("unsupported %s, use only fields, indexes and wildcards", feature)
+}
+
+// parsePathSteps parses an expression, e.g. ".rooms[*].title".
+func parsePathSteps(expr string) ([]pathStep, error) {
+	expr = strings.TrimSpace(expr)
+	if keyword, _, _ := strings.Cut(expr, " "); keyword == "range" || keyword == "end" {
+		return nil, errUnsupported("range/end")
+	}
+	expr = strings.TrimPrefix(expr, "$")
+	if expr == "" {
+		return nil, nil
+	}
+	if expr[0] != '.' && expr[0] != '[' {
+		return nil, fmt.Errorf("expression %q must start with . or $", expr)
+	}
+
+	var steps []pathStep
+	for i := 0; i < len(expr); {
+		switch expr[i] {
+		case '.':
+			i++
+			switch {
+			case i == len(expr) || expr[i] == '[':
+				// "." alone or before a bracket selects the current value.
+			case expr[i] == '.':
+				return nil, errUnsupported("recursive descent (..)")
+			case expr[i] == '*':
+				steps = append(steps, pathStep{kind: wildcardStep})
+				i++
+			default:
+				name := fieldName(expr[i:])
+				if name == "" {
+					return nil, fmt.Errorf("unexpected %q in %q", expr[i], expr)
+				}
+				steps = append(steps, pathStep{kind: fieldStep, field: name})
+				i += len(name)
+			}
+		case '[':
+			end := indexUnquoted(expr[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ in %q", expr)
+			}
+			step, err := parseBracket(expr[i+1 : i+end])
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+			i += end + 1
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q", expr[i], expr)
+		}
+	}
+
+	return steps, nil
+}
+
+// fieldName returns the field name at the start of s.
+func fieldName(s string) string {
+	for i, r := range s {
+		if r != '_' && r != '-' && (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// parseBracket parses the content of brackets: "*", an index or a
+// quoted field name.
+func parseBracket(content string) (pathStep, error) {
+	content = strings.TrimSpace(content)
+
+	if content == "*" {
+		return pathStep{kind: wildcardStep}, nil
+	}
+	if strings.HasPrefix(content, "?") {
+		return pathStep{}, errUnsupported("filter [" + content + "]")
+	}
+	if content != "" && (content[0] == '\'' || content[0] == '"') {
+		end := strings.IndexByte(content[1:], content[0]) + 1
+		switch {
+		case end == len(content)-1:
+			return pathStep{kind: fieldStep, field: content[1:end]}, nil
+		case end > 0 && strings.HasPrefix(strings.TrimSpace(content[end+1:]), ","):
+			return pathStep{}, errUnsupported("union [" + content + "]")
+		default:
+			return pathStep{}, fmt.Errorf("invalid field name [%s]", content)
+		}
+	}
+	if strings.Contains(content, ",") {
+		return pathStep{}, errUnsupported("union [" + content + "]")
+	}
+	if strings.Contains(content, ":") {
+		return pathStep{}, errUnsupported("slice [" + content + "]")
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil {
+		return pathStep{}, fmt.Errorf("invalid index [%s], use a number, * or a quoted field name", content)
+	}
+
+	return pathStep{kind: indexStep, index: index}, nil
+}
+
+// execute applies the template to the JSON encoding of v.
+func (p *jsonPath) execute(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshall result: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var root any
+	if err := decoder.Decode(&root); err != nil {
+		return "", fmt.Errorf("failed to unmarshall result: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, part := range p.parts {
+		if !part.expr {
+			sb.WriteString(part.text)
+			continue
+		}
+
+		values, err := evaluate(root, part.steps)
+		if err != nil {
+			return "", fmt.Errorf("failed to execute JSONPath %q: %w", p.source, err)
+		}
+
+		for i, value := range values {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(formatJSONValue(value))
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// evaluate returns the values matched by the steps.
+func evaluate(root any, steps []pathStep) ([]any, error) {
+	values := []any{root}
+	path := ""
+
+	for _, step := range steps {
+		var next []any
+		for _, value := range values {
+			switch step.kind {
+			case fieldStep:
+				object, ok := value.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("%s is not an object", describePath(path))
+				}
+				field, ok := object[step.field]
+				if !ok {
+					return nil, fmt.Errorf("%s is not found", describePath(path+step.String()))
+				}
+				next = append(next, field)
+			case indexStep:
+				array, ok := value.([]any)
+				if !ok {
+					return nil, fmt.Errorf("%s is not an array", describePath(path))
+				}
+				index := step.index
+				if index < 0 {
+					index += len(array)
+				}
+				if index < 0 || index >= len(array) {
+					return nil, fmt.Errorf("index %s is out of range, %s has %d elements", step, describePath(path), len(array))
+				}
+				next = append(next, array[index])
+			case wildcardStep:
+				switch value := value.(type) {
+				case []any:
+					next = append(next, value...)
+				case map[string]any:
+					keys := make([]string, 0, len(value))
+					for key := range value {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+					for _, key := range keys {
+						next = append(next, value[key])
+					}
+				default:
+					return nil, fmt.Errorf("%s is neither an array nor an object", describePath(path))
+				}
+			}
+		}
+
+		values = next
+		path += step.String()
+	}
+
+	return values, nil
+}
+
+func describePath(path string) string {
+	if path == "" {
+		return "the result"
+	}
+	return path
+}
+
+// formatJSONValue formats a value matched by an expression: the strings
+// and numbers as is, the other values as JSON.
+func formatJSONValue(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,93 @@
+package feedback
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONPath(t *testing.T) {
+	data := map[string]any{
+		"name": "devices/home",
+		"rooms": []map[string]any{
+			{"title": "Kitchen", "airTemperature": 20.5},
+			{"title": "Bedroom", "airTemperature": 18},
+		},
+		"labels": map[string]any{"a}b": "braces", "x]y": "brackets"},
+	}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"{.name}", "devices/home"},
+		{"{$.rooms[*].title}", "Kitchen Bedroom"},
+		{"{.rooms[-1]['title']}={.rooms[-1].airTemperature}", "Bedroom=18"},
+		{"Kitchen: {.rooms[0].airTemperature}", "Kitchen: 20.5"},
+		{"{.rooms[0].*}", "20.5 Kitchen"},
+		{"{.rooms[1]}", `{"airTemperature":18,"title":"Bedroom"}`},
+		{"{.labels['a}b']}", "braces"},
+		{`{.labels["x]y"]}`, "brackets"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			// Arrange
+			path, err := parseJSONPath(tt.template)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			// Act
+			got, err := path.execute(data)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestJSONPath_Errors(t *testing.T) {
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"{.rooms", "unclosed {"},
+		{"{rooms}", "must start with . or $"},
+		{"{.rooms[x]}", "invalid index [x]"},
+		{"{.rooms[5]}", "index [5] is out of range, .rooms has 2 elements"},
+		{"{.rooms[*].humidity}", ".rooms[*].humidity is not found"},
+		{"{.name[0]}", ".name is not an array"},
+		{"{.rooms[?(@.title=='Kitchen')].title}", "unsupported filter"},
+		{"{.rooms[0:1].title}", "unsupported slice [0:1]"},
+		{"{.rooms[0,1].title}", "unsupported union [0,1]"},
+		{"{.rooms[0]['title','name']}", "unsupported union"},
+		{"{..title}", "unsupported recursive descent"},
+		{"{range .rooms[*]}{.title}{end}", "unsupported range/end"},
+		{"{.rooms['title}", "unclosed {"},
+	}
+
+	data := map[string]any{
+		"name":  "devices/home",
+		"rooms": []map[string]any{{"title": "Kitchen"}, {"title": "Bedroom"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			// Act
+			path, err := parseJSONPath(tt.template)
+			if err == nil {
+				_, err = path.execute(data)
+			}
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/zmoog/ws/v2/ws"
+)
+
+// Recorder appends the readings of all the devices in an account
+// to a Store.
+type Recorder struct {
+	client *ws.Client
+	store  *Store
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewRecorder creates a recorder for the devices returned by client.
+// The logger reports the recorded and the failed polls, nothing is
+// logged if it is nil.
+func NewRecorder(client *ws.Client, store *Store, logger *slog.Logger) *Recorder {
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	return &Recorder{
+		client: client,
+		store:  store,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Run records the devices every interval until ctx is done.
+// A failed poll is logged and retried at the next interval.
+func (r *Recorder) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.Poll(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			r.logger.ErrorContext(ctx, "failed to record devices", slog.Any("error", err))
+		default:
+			r.logger.InfoContext(ctx, "recorded samples", slog.Int("samples", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetches the current state of all the devices in the account and
+// appends a sample for each room. It returns the number of samples.
+//
+// Nothing is recorded if a device cannot be fetched, so all the samples
+// of a poll share the same time.
+func (r *Recorder) Poll(ctx context.Context) (int, error) {
+	list, err := r.client.ListDevicesContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	now := r.now()
+
+	var samples []Sample
+	for _, d := range list {
+		device, err := r.client.GetDeviceContext(ctx, d.Name)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get device %s: %w", d.Name, err)
+		}
+		samples = append(samples, Samples(device, now)...)
+	}
+
+	if err := r.store.Append(samples...); err != nil {
+		return 0, err
+	}
+
+	return len(samples), nil
+}
@@ -0,0 +1,53 @@
+package history
+
+import (
+	"math"
+	"time"
+)
+
+// Series returns a value of the samples for n buckets of the same
+// duration between since and until, averaging the samples in each
+// bucket, e.g. to draw a chart n characters wide.
+//
+// The buckets without samples are NaN. The series is empty if n is not
+// positive or until is not after since.
+func Series(samples []Sample, value func(Sample) float64, since, until time.Time, n int) []float64 {
+	span := until.Sub(since)
+	if span <= 0 || n <= 0 {
+		return nil
+	}
+
+	sums := make([]float64, n)
+	counts := make([]int, n)
+
+	for _, sample := range samples {
+		if sample.Time.Before(since) || !sample.Time.Before(until) {
+			continue
+		}
+
+		i := int(int64(sample.Time.Sub(since)) * int64(n) / int64(span))
+		sums[i] += value(sample)
+		counts[i]++
+	}
+
+	series := make([]float64, n)
+	for i := range series {
+		if counts[i] == 0 {
+			series[i] = math.NaN()
+			continue
+		}
+		series[i] = sums[i] / float64(counts[i])
+	}
+
+	return series
+}
+
+// AirTemperature returns the air temperature of a sample.
+func AirTemperature(s Sample) float64 {
+	return s.AirTemperature
+}
+
+// SetpointTemperature returns the desired temperature of a sample.
+func SetpointTemperature(s Sample) float64 {
+	return s.SetpointTemperature
+}
@@ -0,0 +1,217 @@
+// Package history stores the readings of the rooms of Wavin Sentio
+// devices in a local database, to look at trends instead of snapshots.
+//
+// The samples are appended by a Recorder polling the devices at regular
+// intervals, and read back with Store.Query.
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zmoog/ws/v2/ws"
+	bolt "go.etcd.io/bbolt"
+)
+
+// openTimeout is the maximum time to wait for the lock of the database
+// file, held by another process while it writes.
+const openTimeout = 5 * time.Second
+
+var samplesBucket = []byte("samples")
+
+// ErrNoHistory is returned when querying a database that has not been
+// created yet.
+var ErrNoHistory = errors.New("no history recorded")
+
+// Sample is the reading of a room at a point in time.
+type Sample struct {
+	Time                time.Time            `json:"time"`
+	Device              string               `json:"device"`
+	RoomID              string               `json:"roomId"`
+	Room                string               `json:"room"`
+	RoomPersonalized    string               `json:"roomPersonalized,omitempty"`
+	AirTemperature      float64              `json:"airTemperature"`
+	Humidity            float64              `json:"humidity"`
+	SetpointTemperature float64              `json:"setpointTemperature"`
+	TemperatureState    ws.TemperatureState  `json:"temperatureState"`
+	DehumidifierState   ws.DehumidifierState `json:"dehumidifierState"`
+	OutdoorTemperature  *float64             `json:"outdoorTemperature,omitempty"`
+}
+
+// Samples returns a sample for each room of the device, taken at t.
+//
+// The outdoor temperature is the one of the first outdoor sensor, and
+// is nil when the device has none.
+func Samples(device ws.Device, t time.Time) []Sample {
+	sentio := device.LastConfig.Sentio
+
+	var outdoor *float64
+	if len(sentio.OutdoorTemperatureSensors) > 0 {
+		temperature := sentio.OutdoorTemperatureSensors[0].OutdoorTemperature
+		outdoor = &temperature
+	}
+
+	samples := make([]Sample, 0, len(sentio.Rooms))
+	for _, room := range sentio.Rooms {
+		samples = append(samples, Sample{
+			Time:                t.UTC(),
+			Device:              device.Name,
+			RoomID:              room.ID,
+			Room:                room.Title,
+			RoomPersonalized:    room.TitlePersonalized,
+			AirTemperature:      room.AirTemperature,
+			Humidity:            room.Humidity,
+			SetpointTemperature: room.SetpointTemperature,
+			TemperatureState:    room.TemperatureState,
+			DehumidifierState:   room.DehumidifierState,
+			OutdoorTemperature:  outdoor,
+		})
+	}
+
+	return samples
+}
+
+// Query selects the samples returned by Store.Query.
+type Query struct {
+	// Device is the name of the device, any device if empty.
+	Device string
+	// Room is the ID, the title or the personalized title of the room,
+	// any room if empty.
+	Room string
+	// Since is the time of the oldest sample, inclusive.
+	Since time.Time
+	// Until is the time of the newest sample, exclusive.
+	// There is no upper bound if zero.
+	Until time.Time
+}
+
+func (q Query) matches(s Sample) bool {
+	if q.Device != "" && s.Device != q.Device {
+		return false
+	}
+	if q.Room != "" && s.RoomID != q.Room && !strings.EqualFold(s.Room, q.Room) &&
+		(s.RoomPersonalized == "" || !strings.EqualFold(s.RoomPersonalized, q.Room)) {
+		return false
+	}
+
+	return true
+}
+
+// Store is a history database backed by a bbolt file.
+//
+// The file is opened for the duration of each call only, so a long
+// running recorder does not lock out the queries from other processes.
+type Store struct {
+	path string
+}
+
+// NewStore creates a store for the database at path. The file and its
+// directory are created by the first Append.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the path of the database file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append adds the samples to the database.
+func (s *Store) Append(samples ...Sample) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	db, err := bolt.Open(s.path, 0o600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open history %s: %w", s.path, err)
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(samplesBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, sample := range samples {
+			value, err := json.Marshal(sample)
+			if err != nil {
+				return fmt.Errorf("failed to marshal sample: %w", err)
+			}
+			if err := bucket.Put(sampleKey(sample), value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Query returns the samples matching q, oldest first.
+func (s *Store) Query(q Query) ([]Sample, error) {
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w in %s", ErrNoHistory, s.path)
+	}
+
+	db, err := bolt.Open(s.path, 0o600, &bolt.Options{Timeout: openTimeout, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history %s: %w", s.path, err)
+	}
+	defer db.Close()
+
+	var samples []Sample
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(samplesBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.Seek(timeKey(q.Since)); k != nil; k, v = cursor.Next() {
+			if !q.Until.IsZero() && !keyTime(k).Before(q.Until) {
+				break
+			}
+
+			var sample Sample
+			if err := json.Unmarshal(v, &sample); err != nil {
+				return fmt.Errorf("failed to unmarshal sample: %w", err)
+			}
+			if q.matches(sample) {
+				samples = append(samples, sample)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return samples, nil
+}
+
+// sampleKey returns the key of a sample: the time, so the samples are
+// sorted chronologically, followed by the device and the room to keep
+// the samples taken at the same time apart.
+func sampleKey(s Sample) []byte {
+	return append(timeKey(s.Time), []byte(s.Device+"/"+s.RoomID)...)
+}
+
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	if !t.IsZero() {
+		binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	}
+	return key
+}
+
+func keyTime(key []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key[:8])))
+}
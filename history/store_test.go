@@ -0,0 +1,203 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+func TestStore_Query(t *testing.T) {
+	// Arrange
+	store := NewStore(filepath.Join(t.TempDir(), "ws", "history.db"))
+	start := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	var samples []Sample
+	for i := 0; i < 3; i++ {
+		at := start.Add(time.Duration(i) * time.Hour)
+		samples = append(samples,
+			Sample{Time: at, Device: "devices/home", RoomID: "room-1", Room: "Kitchen", AirTemperature: 20 + float64(i)},
+			Sample{Time: at, Device: "devices/home", RoomID: "room-2", Room: "Bedroom", AirTemperature: 18},
+		)
+	}
+	if err := store.Append(samples...); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Act
+	got, err := store.Query(Query{Room: "kitchen", Since: start.Add(time.Hour)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 samples, got %d", len(got))
+	}
+	if got[0].AirTemperature != 21 || got[1].AirTemperature != 22 {
+		t.Errorf("Expected samples 21 and 22 in order, got %v and %v", got[0].AirTemperature, got[1].AirTemperature)
+	}
+	if !got[0].Time.Equal(start.Add(time.Hour)) {
+		t.Errorf("Expected the first sample at %s, got %s", start.Add(time.Hour), got[0].Time)
+	}
+}
+
+func TestStore_QueryRoom(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.db"))
+	at := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	err := store.Append(
+		Sample{Time: at, Device: "devices/home", RoomID: "room-1", Room: "Kitchen", RoomPersonalized: "Cucina"},
+		Sample{Time: at, Device: "devices/home", RoomID: "room-2", Room: "Bedroom"},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		room     string
+		expected []string
+	}{
+		{"any room", "", []string{"room-1", "room-2"}},
+		{"ID", "room-2", []string{"room-2"}},
+		{"title", "KITCHEN", []string{"room-1"}},
+		{"personalized title", "cucina", []string{"room-1"}},
+		{"unknown room", "Attic", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			samples, err := store.Query(Query{Room: tt.room})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			var ids []string
+			for _, sample := range samples {
+				ids = append(ids, sample.RoomID)
+			}
+			if !slices.Equal(ids, tt.expected) {
+				t.Errorf("Expected rooms %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+func TestStore_QueryNoHistory(t *testing.T) {
+	// Arrange
+	store := NewStore(filepath.Join(t.TempDir(), "history.db"))
+
+	// Act
+	_, err := store.Query(Query{})
+
+	// Assert
+	if !errors.Is(err, ErrNoHistory) {
+		t.Fatalf("Expected ErrNoHistory, got %v", err)
+	}
+}
+
+func TestRecorder_Poll(t *testing.T) {
+	// Arrange
+	kitchen := wstest.NewRoom("room-1", "Kitchen")
+	kitchen.TitlePersonalized = "Cucina"
+	server := wstest.NewServer(
+		wstest.NewDevice("devices/home", kitchen, wstest.NewRoom("room-2", "Bedroom")),
+	)
+	defer server.Close()
+
+	store := NewStore(filepath.Join(t.TempDir(), "history.db"))
+	recorder := NewRecorder(server.Client(), store, nil)
+
+	// Act
+	n, err := recorder.Poll(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("Expected 2 samples, got %d", n)
+	}
+
+	samples, err := store.Query(Query{Device: "devices/home", Room: "Cucina"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(samples) != 1 {
+		t.Fatalf("Expected 1 sample, got %d", len(samples))
+	}
+	if samples[0].RoomID != "room-1" || samples[0].RoomPersonalized != "Cucina" {
+		t.Errorf("Expected room-1 with personalized title Cucina, got %s and %q", samples[0].RoomID, samples[0].RoomPersonalized)
+	}
+	if samples[0].AirTemperature != 20.5 || samples[0].SetpointTemperature != 21 {
+		t.Errorf("Expected air temperature 20.5 and setpoint 21, got %v and %v", samples[0].AirTemperature, samples[0].SetpointTemperature)
+	}
+	if samples[0].OutdoorTemperature == nil || *samples[0].OutdoorTemperature != 8.5 {
+		t.Errorf("Expected outdoor temperature 8.5, got %v", samples[0].OutdoorTemperature)
+	}
+}
+
+func TestSeries(t *testing.T) {
+	// Arrange
+	since := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	samples := []Sample{
+		{Time: since, AirTemperature: 20},
+		{Time: since.Add(30 * time.Minute), AirTemperature: 21},
+		{Time: since.Add(3 * time.Hour), AirTemperature: 23},
+		{Time: since.Add(4 * time.Hour), AirTemperature: 30},
+	}
+
+	// Act
+	series := Series(samples, AirTemperature, since, since.Add(4*time.Hour), 4)
+
+	// Assert
+	if len(series) != 4 {
+		t.Fatalf("Expected 4 values, got %d", len(series))
+	}
+	if series[0] != 20.5 {
+		t.Errorf("Expected the first bucket to be 20.5, got %v", series[0])
+	}
+	if !math.IsNaN(series[1]) || !math.IsNaN(series[2]) {
+		t.Errorf("Expected the empty buckets to be NaN, got %v and %v", series[1], series[2])
+	}
+	if series[3] != 23 {
+		t.Errorf("Expected the last bucket to be 23, got %v", series[3])
+	}
+}
+
+func TestSeries_Empty(t *testing.T) {
+	since := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	samples := []Sample{{Time: since, AirTemperature: 20}}
+
+	tests := []struct {
+		name  string
+		until time.Time
+		n     int
+	}{
+		{"no buckets", since.Add(time.Hour), 0},
+		{"negative buckets", since.Add(time.Hour), -1},
+		{"empty span", since, 4},
+		{"until before since", since.Add(-time.Hour), 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			series := Series(samples, AirTemperature, since, tt.until, tt.n)
+
+			// Assert
+			if len(series) != 0 {
+				t.Errorf("Expected an empty series, got %v", series)
+			}
+		})
+	}
+}
@@ -0,0 +1,407 @@
+// Package mqttbridge publishes the rooms of Wavin Sentio devices to an
+// MQTT broker as Home Assistant climate entities.
+//
+// Each room is announced using the Home Assistant MQTT discovery protocol,
+// its state is published at regular intervals, and the desired temperature
+// set from Home Assistant is forwarded to the device.
+package mqttbridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/zmoog/ws/v2/ws"
+)
+
+const (
+	// DefaultDiscoveryPrefix is the default Home Assistant discovery prefix.
+	DefaultDiscoveryPrefix = "homeassistant"
+	// DefaultTopicPrefix is the default prefix of the state and command topics.
+	DefaultTopicPrefix = "ws"
+
+	qos = 1
+
+	// commandTimeout is the maximum time to apply a command from the broker.
+	commandTimeout = 30 * time.Second
+)
+
+// Config is the configuration of a Bridge.
+type Config struct {
+	// DiscoveryPrefix is the Home Assistant discovery prefix,
+	// DefaultDiscoveryPrefix if empty.
+	DiscoveryPrefix string
+	// TopicPrefix is the prefix of the state, command and availability
+	// topics, DefaultTopicPrefix if empty.
+	TopicPrefix string
+	// Logger reports the published states and the received commands.
+	Logger *slog.Logger
+}
+
+// Bridge connects the devices in a Wavin Sentio account to an MQTT broker.
+type Bridge struct {
+	client *ws.Client
+	config Config
+
+	refresh chan struct{}
+
+	mu        sync.Mutex
+	mqtt      mqtt.Client
+	rooms     map[string]roomRef // command topic -> room
+	announced map[string]bool    // state topic -> discovery published
+}
+
+// roomRef identifies a room on a device.
+type roomRef struct {
+	deviceName string
+	roomID     string
+}
+
+// New creates a bridge publishing the devices returned by client.
+func New(client *ws.Client, config Config) *Bridge {
+	if config.DiscoveryPrefix == "" {
+		config.DiscoveryPrefix = DefaultDiscoveryPrefix
+	}
+	if config.TopicPrefix == "" {
+		config.TopicPrefix = DefaultTopicPrefix
+	}
+	if config.Logger == nil {
+		config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	return &Bridge{
+		client:    client,
+		config:    config,
+		refresh:   make(chan struct{}, 1),
+		rooms:     make(map[string]roomRef),
+		announced: make(map[string]bool),
+	}
+}
+
+// AvailabilityTopic returns the topic where the bridge publishes
+// "online" when connected and "offline" when disconnected.
+func (b *Bridge) AvailabilityTopic() string {
+	return b.config.TopicPrefix + "/status"
+}
+
+// Configure sets the will message and the on connect handler
+// of the MQTT client options used with the bridge.
+func (b *Bridge) Configure(opts *mqtt.ClientOptions) {
+	opts.SetWill(b.AvailabilityTopic(), "offline", qos, true)
+	opts.SetOnConnectHandler(b.onConnect)
+}
+
+// onConnect subscribes to the command topics and announces the rooms
+// again, since the broker may have lost them while disconnected.
+func (b *Bridge) onConnect(client mqtt.Client) {
+	b.mu.Lock()
+	b.mqtt = client
+	b.announced = make(map[string]bool)
+	b.mu.Unlock()
+
+	client.Subscribe(b.config.TopicPrefix+"/+/+/temperature/set", qos, b.handleSetTemperature)
+	client.Publish(b.AvailabilityTopic(), qos, true, "online")
+
+	select {
+	case b.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// Run publishes the state of the rooms to client every interval until
+// ctx is done, then publishes "offline" on the availability topic.
+func (b *Bridge) Run(ctx context.Context, client mqtt.Client, interval time.Duration) error {
+	b.mu.Lock()
+	b.mqtt = client
+	b.mu.Unlock()
+
+	defer client.Publish(b.AvailabilityTopic(), qos, true, "offline").WaitTimeout(time.Second)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.Poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			b.config.Logger.ErrorContext(ctx, "failed to poll devices", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-b.refresh:
+		}
+	}
+}
+
+// Poll fetches all the devices and publishes the state of their rooms,
+// announcing the rooms not yet known to Home Assistant.
+func (b *Bridge) Poll(ctx context.Context) error {
+	devices, err := b.client.ListDevicesContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	for _, d := range devices {
+		device, err := b.client.GetDeviceContext(ctx, d.Name)
+		if err != nil {
+			return fmt.Errorf("failed to get device %s: %w", d.Name, err)
+		}
+
+		for _, room := range device.LastConfig.Sentio.Rooms {
+			b.mu.Lock()
+			b.rooms[b.roomTopic(device.Name, room.ID, "temperature/set")] = roomRef{deviceName: device.Name, roomID: room.ID}
+			b.mu.Unlock()
+
+			b.publishRoom(device, room)
+		}
+	}
+
+	return nil
+}
+
+// publishRoom publishes the discovery message of the room if needed,
+// and its current state.
+func (b *Bridge) publishRoom(device ws.Device, room ws.Room) {
+	stateTopic := b.roomTopic(device.Name, room.ID, "state")
+
+	b.mu.Lock()
+	announced := b.announced[stateTopic]
+	b.announced[stateTopic] = true
+	b.mu.Unlock()
+
+	if !announced {
+		b.publishJSON(b.discoveryTopic(device.Name, room.ID), true, b.discoveryMessage(device, room))
+	}
+
+	b.publishJSON(stateTopic, true, newRoomState(device, room))
+	b.config.Logger.Debug("published room state", slog.String("topic", stateTopic))
+}
+
+func (b *Bridge) publishJSON(topic string, retained bool, v any) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		b.config.Logger.Error("failed to marshal message", slog.String("topic", topic), slog.Any("error", err))
+		return
+	}
+
+	b.mu.Lock()
+	client := b.mqtt
+	b.mu.Unlock()
+
+	client.Publish(topic, qos, retained, payload)
+}
+
+// handleSetTemperature handles the messages on the command topics.
+func (b *Bridge) handleSetTemperature(_ mqtt.Client, msg mqtt.Message) {
+	logger := b.config.Logger.With(slog.String("topic", msg.Topic()))
+
+	b.mu.Lock()
+	ref, ok := b.rooms[msg.Topic()]
+	b.mu.Unlock()
+	if !ok {
+		logger.Warn("ignoring command for unknown room")
+		return
+	}
+	deviceName, roomID := ref.deviceName, ref.roomID
+
+	temperature, err := strconv.ParseFloat(strings.TrimSpace(string(msg.Payload())), 64)
+	if err != nil {
+		logger.Warn("ignoring invalid temperature", slog.String("payload", string(msg.Payload())))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	if _, err := b.client.SetRoomTemperatureContext(ctx, deviceName, roomID, temperature); err != nil {
+		logger.Error("failed to set temperature", slog.Float64("temperature", temperature), slog.Any("error", err))
+		return
+	}
+	logger.Info("set temperature", slog.String("device", deviceName), slog.String("room", roomID), slog.Float64("temperature", temperature))
+
+	// Publish the new state right away instead of waiting
+	// for the next poll.
+	device, err := b.client.GetDeviceContext(ctx, deviceName)
+	if err != nil {
+		logger.Error("failed to get device", slog.Any("error", err))
+		return
+	}
+	if room, ok := device.Room(roomID); ok {
+		b.publishRoom(device, room)
+	}
+}
+
+func (b *Bridge) roomTopic(deviceName, roomID, suffix string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", b.config.TopicPrefix, nodeID(deviceName), topicSafe(roomID), suffix)
+}
+
+func (b *Bridge) discoveryTopic(deviceName, roomID string) string {
+	return fmt.Sprintf("%s/climate/%s/%s/config", b.config.DiscoveryPrefix, nodeID(deviceName), topicSafe(roomID))
+}
+
+// discoveryMessage is the Home Assistant MQTT discovery payload of a
+// climate entity.
+type discoveryMessage struct {
+	Name                       string          `json:"name"`
+	UniqueID                   string          `json:"unique_id"`
+	AvailabilityTopic          string          `json:"availability_topic"`
+	CurrentTemperatureTopic    string          `json:"current_temperature_topic"`
+	CurrentTemperatureTemplate string          `json:"current_temperature_template"`
+	CurrentHumidityTopic       string          `json:"current_humidity_topic"`
+	CurrentHumidityTemplate    string          `json:"current_humidity_template"`
+	TemperatureStateTopic      string          `json:"temperature_state_topic"`
+	TemperatureStateTemplate   string          `json:"temperature_state_template"`
+	TemperatureCommandTopic    string          `json:"temperature_command_topic"`
+	ModeStateTopic             string          `json:"mode_state_topic"`
+	ModeStateTemplate          string          `json:"mode_state_template"`
+	Modes                      []string        `json:"modes"`
+	ActionTopic                string          `json:"action_topic"`
+	ActionTemplate             string          `json:"action_template"`
+	MinTemp                    float64         `json:"min_temp"`
+	MaxTemp                    float64         `json:"max_temp"`
+	TempStep                   float64         `json:"temp_step"`
+	Precision                  float64         `json:"precision"`
+	TemperatureUnit            string          `json:"temperature_unit"`
+	Device                     discoveryDevice `json:"device"`
+}
+
+type discoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+	SerialNumber string   `json:"serial_number,omitempty"`
+	SWVersion    string   `json:"sw_version,omitempty"`
+}
+
+func (b *Bridge) discoveryMessage(device ws.Device, room ws.Room) discoveryMessage {
+	stateTopic := b.roomTopic(device.Name, room.ID, "state")
+
+	modes := make([]string, 0, len(device.LastConfig.Sentio.AvailableHcModes))
+	for _, hcMode := range device.LastConfig.Sentio.AvailableHcModes {
+		if mode := climateMode(hcMode); mode != "" {
+			modes = append(modes, mode)
+		}
+	}
+	if len(modes) == 0 {
+		modes = append(modes, "heat")
+	}
+
+	title := room.Title
+	if room.TitlePersonalized != "" {
+		title = room.TitlePersonalized
+	}
+
+	deviceTitle := device.LastConfig.Sentio.TitlePersonalized
+	if deviceTitle == "" {
+		deviceTitle = "Wavin Sentio " + nodeID(device.Name)
+	}
+
+	return discoveryMessage{
+		Name:                       title,
+		UniqueID:                   fmt.Sprintf("ws_%s_%s", nodeID(device.Name), topicSafe(room.ID)),
+		AvailabilityTopic:          b.AvailabilityTopic(),
+		CurrentTemperatureTopic:    stateTopic,
+		CurrentTemperatureTemplate: "{{ value_json.current_temperature }}",
+		CurrentHumidityTopic:       stateTopic,
+		CurrentHumidityTemplate:    "{{ value_json.current_humidity }}",
+		TemperatureStateTopic:      stateTopic,
+		TemperatureStateTemplate:   "{{ value_json.temperature }}",
+		TemperatureCommandTopic:    b.roomTopic(device.Name, room.ID, "temperature/set"),
+		ModeStateTopic:             stateTopic,
+		ModeStateTemplate:          "{{ value_json.mode }}",
+		Modes:                      modes,
+		ActionTopic:                stateTopic,
+		ActionTemplate:             "{{ value_json.action }}",
+		MinTemp:                    room.MinSetpointTemperature,
+		MaxTemp:                    room.MaxSetpointTemperature,
+		TempStep:                   0.5,
+		Precision:                  0.1,
+		TemperatureUnit:            "C",
+		Device: discoveryDevice{
+			Identifiers:  []string{"ws_" + nodeID(device.Name)},
+			Name:         deviceTitle,
+			Manufacturer: "Wavin",
+			Model:        "Sentio",
+			SerialNumber: device.SerialNumber,
+			SWVersion:    device.FirmwareInstalled,
+		},
+	}
+}
+
+// roomState is the payload published on the state topic of a room.
+type roomState struct {
+	CurrentTemperature float64 `json:"current_temperature"`
+	CurrentHumidity    float64 `json:"current_humidity"`
+	Temperature        float64 `json:"temperature"`
+	Mode               string  `json:"mode"`
+	Action             string  `json:"action"`
+}
+
+func newRoomState(device ws.Device, room ws.Room) roomState {
+	mode := climateMode(device.LastConfig.Sentio.HcMode)
+	if mode == "" {
+		mode = "heat"
+	}
+
+	return roomState{
+		CurrentTemperature: room.AirTemperature,
+		CurrentHumidity:    room.Humidity,
+		Temperature:        room.SetpointTemperature,
+		Mode:               mode,
+		Action:             climateAction(room.TemperatureState),
+	}
+}
+
+// climateMode maps a Sentio heating/cooling mode to a Home Assistant
+// climate mode.
+func climateMode(hcMode ws.HcMode) string {
+	switch hcMode {
+	case ws.HcModeHeating:
+		return "heat"
+	case ws.HcModeCooling:
+		return "cool"
+	default:
+		return ""
+	}
+}
+
+// climateAction maps a Sentio temperature state to a Home Assistant
+// climate action.
+func climateAction(temperatureState ws.TemperatureState) string {
+	switch temperatureState {
+	case ws.TemperatureStateHeating:
+		return "heating"
+	case ws.TemperatureStateCooling:
+		return "cooling"
+	case ws.TemperatureStateIdle:
+		return "idle"
+	default:
+		return "off"
+	}
+}
+
+var unsafeTopicChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// nodeID returns the MQTT node ID of a device, e.g. "abc" for "devices/abc".
+func nodeID(deviceName string) string {
+	return topicSafe(strings.TrimPrefix(deviceName, "devices/"))
+}
+
+// topicSafe replaces the characters not allowed in a discovery
+// topic segment.
+func topicSafe(s string) string {
+	return unsafeTopicChars.ReplaceAllString(s, "_")
+}
@@ -0,0 +1,154 @@
+package mqttbridge
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+// fakeClient records the messages published by the bridge.
+type fakeClient struct {
+	mqtt.Client
+
+	mu        sync.Mutex
+	published map[string][]byte
+	handlers  map[string]mqtt.MessageHandler
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		published: make(map[string][]byte),
+		handlers:  make(map[string]mqtt.MessageHandler),
+	}
+}
+
+func (c *fakeClient) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch p := payload.(type) {
+	case string:
+		c.published[topic] = []byte(p)
+	case []byte:
+		c.published[topic] = p
+	}
+	return doneToken{}
+}
+
+func (c *fakeClient) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.handlers[topic] = callback
+	return doneToken{}
+}
+
+func (c *fakeClient) message(topic string) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.published[topic]
+}
+
+type doneToken struct{}
+
+func (doneToken) Wait() bool                     { return true }
+func (doneToken) WaitTimeout(time.Duration) bool { return true }
+func (doneToken) Done() <-chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}
+func (doneToken) Error() error { return nil }
+
+type fakeMessage struct {
+	mqtt.Message
+
+	topic   string
+	payload []byte
+}
+
+func (m fakeMessage) Topic() string   { return m.topic }
+func (m fakeMessage) Payload() []byte { return m.payload }
+
+func TestBridge_Poll(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(
+		wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")),
+	)
+	defer server.Close()
+
+	client := newFakeClient()
+	bridge := New(server.Client(), Config{})
+	bridge.onConnect(client)
+
+	// Act
+	err := bridge.Poll(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := string(client.message("ws/status")); got != "online" {
+		t.Errorf("Expected availability online, got %q", got)
+	}
+
+	var discovery discoveryMessage
+	if err := json.Unmarshal(client.message("homeassistant/climate/home/room-1/config"), &discovery); err != nil {
+		t.Fatalf("Expected discovery message, got %v", err)
+	}
+	if discovery.Name != "Kitchen" || discovery.TemperatureCommandTopic != "ws/home/room-1/temperature/set" {
+		t.Errorf("Unexpected discovery message %+v", discovery)
+	}
+
+	var state roomState
+	if err := json.Unmarshal(client.message("ws/home/room-1/state"), &state); err != nil {
+		t.Fatalf("Expected state message, got %v", err)
+	}
+	if state.CurrentTemperature != 20.5 || state.Temperature != 21 || state.Mode != "heat" || state.Action != "idle" {
+		t.Errorf("Unexpected state %+v", state)
+	}
+}
+
+func TestBridge_SetTemperature(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(
+		wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")),
+	)
+	defer server.Close()
+
+	client := newFakeClient()
+	bridge := New(server.Client(), Config{})
+	bridge.onConnect(client)
+	if err := bridge.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	handler := client.handlers["ws/+/+/temperature/set"]
+	if handler == nil {
+		t.Fatal("Expected a subscription to the command topics")
+	}
+
+	// Act
+	handler(client, fakeMessage{topic: "ws/home/room-1/temperature/set", payload: []byte("22.5")})
+
+	// Assert
+	device, _ := server.Device("devices/home")
+	if room, _ := device.Room("room-1"); room.SetpointTemperature != 22.5 {
+		t.Errorf("Expected setpoint 22.5, got %.1f", room.SetpointTemperature)
+	}
+
+	var state roomState
+	if err := json.Unmarshal(client.message("ws/home/room-1/state"), &state); err != nil {
+		t.Fatalf("Expected state message, got %v", err)
+	}
+	if state.Temperature != 22.5 {
+		t.Errorf("Expected published setpoint 22.5, got %.1f", state.Temperature)
+	}
+}
@@ -1,98 +1,620 @@
 package ws
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
 	"net/http"
+	"slices"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/zmoog/ws/v2/ws/identity"
+	"github.com/zmoog/ws/v2/ws/internal/transport"
 )
 
+var (
+	// ErrRoomNotFound is returned when a room does not exist on a device.
+	ErrRoomNotFound = errors.New("room not found")
+
+	// ErrOutOfRange is returned when a value is outside the range
+	// accepted by the device.
+	ErrOutOfRange = errors.New("value out of range")
+
+	// ErrVacationEnd is returned when the end of a vacation
+	// is not in the future.
+	ErrVacationEnd = errors.New("vacation end must be in the future")
+
+	// ErrHcModeNotAvailable is returned when a heating/cooling mode
+	// is not available on the device.
+	ErrHcModeNotAvailable = errors.New("heating/cooling mode not available")
+
+	// ErrNoDehumidificationPreset is returned when a room has no
+	// dehumidification preset for the current heating/cooling mode.
+	ErrNoDehumidificationPreset = errors.New("no dehumidification preset")
+
+	// ErrNoTemperaturePreset is returned when a room has no temperature
+	// preset of a type for the current heating/cooling mode.
+	ErrNoTemperaturePreset = errors.New("no temperature preset")
+
+	// ErrInvalidQuietMode is returned when a quiet mode is not
+	// one of QuietModes.
+	ErrInvalidQuietMode = errors.New("invalid quiet mode")
+)
+
+// idempotentMethods are the BlazeDeviceService methods that are safe
+// to retry after a transient failure.
+var idempotentMethods = map[string]bool{
+	"ListDevices": true,
+	"GetDevice":   true,
+}
+
 type Client struct {
 	client   *http.Client
 	endpoint string
 	identity identity.Manager
+
+	userAgent   string
+	logger      *slog.Logger
+	middlewares []transport.Middleware
+
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
 }
 
-func NewClient(identity identity.Manager, endpoint string) *Client {
-	return &Client{
-		client:   http.DefaultClient,
-		identity: identity,
-		endpoint: endpoint,
+// NewClient creates a client for the BlazeDeviceService at endpoint,
+// using identity to authenticate the requests.
+func NewClient(identity identity.Manager, endpoint string, opts ...Option) *Client {
+	c := &Client{
+		client:     http.DefaultClient,
+		identity:   identity,
+		endpoint:   endpoint,
+		logger:     discardLogger(),
+		maxRetries: DefaultMaxRetries,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.client = transport.Wrap(c.client, c.middlewares)
+
+	return c
 }
 
+// ListDevices returns the devices in the account.
 func (c *Client) ListDevices() ([]Device, error) {
-	token, err := c.identity.GetToken()
-	if err != nil {
+	return c.ListDevicesContext(context.Background())
+}
+
+// ListDevicesContext is like ListDevices but uses ctx for the requests.
+func (c *Client) ListDevicesContext(ctx context.Context) ([]Device, error) {
+	var devices Devices
+	if err := c.call(ctx, "ListDevices", struct{}{}, &devices); err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", c.endpoint+"/"+"ListDevices", strings.NewReader("{}"))
+	return devices.Devices, nil
+}
+
+// GetDevice returns the device with the given name.
+func (c *Client) GetDevice(deviceName string) (Device, error) {
+	return c.GetDeviceContext(context.Background(), deviceName)
+}
+
+// GetDeviceContext is like GetDevice but uses ctx for the requests.
+func (c *Client) GetDeviceContext(ctx context.Context, deviceName string) (Device, error) {
+	r := struct {
+		Name string `json:"name"`
+	}{
+		Name: deviceName,
+	}
+
+	var device Device
+	if err := c.call(ctx, "GetDevice", r, &device); err != nil {
+		return Device{}, err
+	}
+
+	return device, nil
+}
+
+// UpdateConfig applies a partial configuration update to a device
+// and returns the updated device.
+//
+// Only the fields set in the update are changed on the device.
+func (c *Client) UpdateConfig(deviceName string, update ConfigUpdate) (Device, error) {
+	return c.UpdateConfigContext(context.Background(), deviceName, update)
+}
+
+// UpdateConfigContext is like UpdateConfig but uses ctx for the requests.
+func (c *Client) UpdateConfigContext(ctx context.Context, deviceName string, update ConfigUpdate) (Device, error) {
+	r := struct {
+		Name   string       `json:"name"`
+		Config ConfigUpdate `json:"config"`
+	}{
+		Name:   deviceName,
+		Config: update,
+	}
+
+	var device Device
+	if err := c.call(ctx, "UpdateConfig", r, &device); err != nil {
+		return Device{}, err
+	}
+
+	return device, nil
+}
+
+// SetRoomTemperature sets the desired temperature of a room and returns
+// the updated room.
+//
+// The room is looked up by ID or title, and the temperature must be within
+// the room's minimum and maximum setpoint temperature.
+func (c *Client) SetRoomTemperature(deviceName, room string, temperature float64) (UpdatedRoom, error) {
+	return c.SetRoomTemperatureContext(context.Background(), deviceName, room, temperature)
+}
+
+// SetRoomTemperatureContext is like SetRoomTemperature but uses ctx
+// for the requests.
+func (c *Client) SetRoomTemperatureContext(ctx context.Context, deviceName, room string, temperature float64) (UpdatedRoom, error) {
+	device, err := c.GetDeviceContext(ctx, deviceName)
 	if err != nil {
-		return nil, err
+		return UpdatedRoom{}, err
 	}
 
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Authorization", "Bearer "+token.ID)
+	current, ok := device.Room(room)
+	if !ok {
+		return UpdatedRoom{}, fmt.Errorf("%w: %s", ErrRoomNotFound, room)
+	}
 
-	resp, err := c.client.Do(req)
+	if temperature < current.MinSetpointTemperature || temperature > current.MaxSetpointTemperature {
+		return UpdatedRoom{}, fmt.Errorf(
+			"%w: temperature %.1f must be between %.1f and %.1f",
+			ErrOutOfRange,
+			temperature,
+			current.MinSetpointTemperature,
+			current.MaxSetpointTemperature,
+		)
+	}
+
+	return c.updateRoom(ctx, deviceName, RoomUpdate{ID: current.ID, SetpointTemperature: &temperature})
+}
+
+// SetRoomHumidity sets the humidity setpoint of a room for the current
+// heating/cooling mode of the device, and returns the updated room.
+//
+// The room is looked up by ID or title, and the setpoint must be within
+// the range of the dehumidification preset.
+func (c *Client) SetRoomHumidity(deviceName, room string, setpoint float64) (UpdatedRoom, error) {
+	return c.SetRoomHumidityContext(context.Background(), deviceName, room, setpoint)
+}
+
+// SetRoomHumidityContext is like SetRoomHumidity but uses ctx
+// for the requests.
+func (c *Client) SetRoomHumidityContext(ctx context.Context, deviceName, room string, setpoint float64) (UpdatedRoom, error) {
+	device, err := c.GetDeviceContext(ctx, deviceName)
+	if err != nil {
+		return UpdatedRoom{}, err
+	}
+
+	current, ok := device.Room(room)
+	if !ok {
+		return UpdatedRoom{}, fmt.Errorf("%w: %s", ErrRoomNotFound, room)
+	}
+
+	mode := device.LastConfig.Sentio.HcMode
+	preset, ok := current.DehumidificationPreset(mode)
+	if !ok {
+		return UpdatedRoom{}, fmt.Errorf("%w: %s has no preset for %s", ErrNoDehumidificationPreset, current.Title, mode)
+	}
+
+	if setpoint < preset.MinHumiditySetpoint || setpoint > preset.MaxHumiditySetpoint {
+		return UpdatedRoom{}, fmt.Errorf(
+			"%w: humidity %.1f must be between %.1f and %.1f",
+			ErrOutOfRange,
+			setpoint,
+			preset.MinHumiditySetpoint,
+			preset.MaxHumiditySetpoint,
+		)
+	}
+
+	return c.updateRoom(ctx, deviceName, RoomUpdate{
+		ID: current.ID,
+		DehumidificationPresets: []DehumidificationPresetUpdate{
+			{HcMode: mode, Setpoint: setpoint},
+		},
+	})
+}
+
+// SetPresetTemperature sets the setpoint of a temperature preset of the
+// given rooms for the current heating/cooling mode of the device, with a
+// single update, and returns the updated rooms.
+//
+// The rooms are looked up by ID or title, and the temperature must be
+// within the range of the preset in each room.
+func (c *Client) SetPresetTemperature(deviceName string, presetType PresetType, temperature float64, rooms ...string) ([]Room, error) {
+	return c.SetPresetTemperatureContext(context.Background(), deviceName, presetType, temperature, rooms...)
+}
+
+// SetPresetTemperatureContext is like SetPresetTemperature but uses ctx
+// for the requests.
+func (c *Client) SetPresetTemperatureContext(ctx context.Context, deviceName string, presetType PresetType, temperature float64, rooms ...string) ([]Room, error) {
+	device, err := c.GetDeviceContext(ctx, deviceName)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close() // nolint
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	mode := device.LastConfig.Sentio.HcMode
+	updates := make([]RoomUpdate, 0, len(rooms))
+	for _, room := range rooms {
+		current, ok := device.Room(room)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrRoomNotFound, room)
+		}
+
+		preset, ok := current.TemperaturePreset(presetType, mode)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no %s preset for %s", ErrNoTemperaturePreset, current.Title, presetType, mode)
+		}
+
+		if temperature < preset.MinSetpointTemperature || temperature > preset.MaxSetpointTemperature {
+			return nil, fmt.Errorf(
+				"%w: temperature %.1f must be between %.1f and %.1f in %s",
+				ErrOutOfRange,
+				temperature,
+				preset.MinSetpointTemperature,
+				preset.MaxSetpointTemperature,
+				current.Title,
+			)
+		}
+
+		updates = append(updates, RoomUpdate{
+			ID: current.ID,
+			TemperaturePresets: []TemperaturePresetUpdate{
+				{Type: presetType, HcMode: mode, SetpointTemperature: temperature},
+			},
+		})
 	}
 
-	var devices Devices
-	if err := json.NewDecoder(resp.Body).Decode(&devices); err != nil {
+	return c.updateRooms(ctx, deviceName, updates)
+}
+
+// ActivatePreset switches a room to a temperature preset, and returns
+// the updated room.
+//
+// The room is looked up by ID or title, and must have the preset for
+// the current heating/cooling mode of the device.
+func (c *Client) ActivatePreset(deviceName, room string, presetType PresetType) (UpdatedRoom, error) {
+	return c.ActivatePresetContext(context.Background(), deviceName, room, presetType)
+}
+
+// ActivatePresetContext is like ActivatePreset but uses ctx
+// for the requests.
+func (c *Client) ActivatePresetContext(ctx context.Context, deviceName, room string, presetType PresetType) (UpdatedRoom, error) {
+	device, err := c.GetDeviceContext(ctx, deviceName)
+	if err != nil {
+		return UpdatedRoom{}, err
+	}
+
+	current, ok := device.Room(room)
+	if !ok {
+		return UpdatedRoom{}, fmt.Errorf("%w: %s", ErrRoomNotFound, room)
+	}
+
+	mode := device.LastConfig.Sentio.HcMode
+	if _, ok := current.TemperaturePreset(presetType, mode); !ok {
+		return UpdatedRoom{}, fmt.Errorf("%w: %s has no %s preset for %s", ErrNoTemperaturePreset, current.Title, presetType, mode)
+	}
+
+	return c.updateRoom(ctx, deviceName, RoomUpdate{ID: current.ID, PresetType: presetType})
+}
+
+// StartVacation turns on the vacation mode of a device until the given
+// time, and returns the updated device.
+func (c *Client) StartVacation(deviceName string, until time.Time) (Device, error) {
+	return c.StartVacationContext(context.Background(), deviceName, until)
+}
+
+// StartVacationContext is like StartVacation but uses ctx
+// for the requests.
+func (c *Client) StartVacationContext(ctx context.Context, deviceName string, until time.Time) (Device, error) {
+	if !until.After(time.Now()) {
+		return Device{}, fmt.Errorf("%w: %s", ErrVacationEnd, until.Format(time.RFC3339))
+	}
+
+	return c.UpdateConfigContext(ctx, deviceName, ConfigUpdate{
+		Sentio: SentioUpdate{
+			VacationSettings: &VacationSettings{
+				VacationMode:      VacationModeOn,
+				VacationModeUntil: until,
+			},
+		},
+	})
+}
+
+// StopVacation turns off the vacation mode of a device, and returns
+// the updated device.
+func (c *Client) StopVacation(deviceName string) (Device, error) {
+	return c.StopVacationContext(context.Background(), deviceName)
+}
+
+// StopVacationContext is like StopVacation but uses ctx
+// for the requests.
+func (c *Client) StopVacationContext(ctx context.Context, deviceName string) (Device, error) {
+	return c.UpdateConfigContext(ctx, deviceName, ConfigUpdate{
+		Sentio: SentioUpdate{
+			VacationSettings: &VacationSettings{VacationMode: VacationModeOff},
+		},
+	})
+}
+
+// SetLockMode locks or unlocks the thermostats of the given rooms with
+// a single update, and returns the updated rooms.
+//
+// The rooms are looked up by ID or title.
+func (c *Client) SetLockMode(deviceName string, mode LockMode, rooms ...string) ([]Room, error) {
+	return c.SetLockModeContext(context.Background(), deviceName, mode, rooms...)
+}
+
+// SetLockModeContext is like SetLockMode but uses ctx for the requests.
+func (c *Client) SetLockModeContext(ctx context.Context, deviceName string, mode LockMode, rooms ...string) ([]Room, error) {
+	device, err := c.GetDeviceContext(ctx, deviceName)
+	if err != nil {
 		return nil, err
 	}
 
-	return devices.Devices, nil
+	updates := make([]RoomUpdate, 0, len(rooms))
+	for _, room := range rooms {
+		current, ok := device.Room(room)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrRoomNotFound, room)
+		}
+		updates = append(updates, RoomUpdate{ID: current.ID, LockMode: mode})
+	}
+
+	return c.updateRooms(ctx, deviceName, updates)
 }
 
-func (c *Client) GetDevice(deviceName string) (Device, error) {
-	token, err := c.identity.GetToken()
+// SetHcMode changes the heating/cooling mode of a device, and returns
+// the updated device.
+//
+// The mode must be one of the available modes of the device.
+func (c *Client) SetHcMode(deviceName string, mode HcMode) (Device, error) {
+	return c.SetHcModeContext(context.Background(), deviceName, mode)
+}
+
+// SetHcModeContext is like SetHcMode but uses ctx for the requests.
+func (c *Client) SetHcModeContext(ctx context.Context, deviceName string, mode HcMode) (Device, error) {
+	device, err := c.GetDeviceContext(ctx, deviceName)
 	if err != nil {
 		return Device{}, err
 	}
 
-	r := struct {
-		Name string `json:"name"`
-	}{
-		Name: deviceName,
+	available := device.LastConfig.Sentio.AvailableHcModes
+	if !slices.Contains(available, mode) {
+		return Device{}, fmt.Errorf("%w: %s (available: %s)", ErrHcModeNotAvailable, string(mode), joinHcModes(available))
+	}
+
+	return c.UpdateConfigContext(ctx, deviceName, ConfigUpdate{
+		Sentio: SentioUpdate{HcMode: mode},
+	})
+}
+
+func joinHcModes(modes []HcMode) string {
+	names := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		names = append(names, string(mode))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// SetStandbyMode turns the standby mode of a device on or off, and
+// returns the updated device.
+func (c *Client) SetStandbyMode(deviceName string, mode StandbyMode) (Device, error) {
+	return c.SetStandbyModeContext(context.Background(), deviceName, mode)
+}
+
+// SetStandbyModeContext is like SetStandbyMode but uses ctx
+// for the requests.
+func (c *Client) SetStandbyModeContext(ctx context.Context, deviceName string, mode StandbyMode) (Device, error) {
+	return c.UpdateConfigContext(ctx, deviceName, ConfigUpdate{
+		Sentio: SentioUpdate{StandbyMode: mode},
+	})
+}
+
+// SetQuietMode changes the quiet mode of a device, and returns
+// the updated device.
+func (c *Client) SetQuietMode(deviceName string, mode QuietMode) (Device, error) {
+	return c.SetQuietModeContext(context.Background(), deviceName, mode)
+}
+
+// SetQuietModeContext is like SetQuietMode but uses ctx for the requests.
+func (c *Client) SetQuietModeContext(ctx context.Context, deviceName string, mode QuietMode) (Device, error) {
+	if !slices.Contains(QuietModes, mode) {
+		return Device{}, fmt.Errorf("%w: %s (valid: %s, %s)", ErrInvalidQuietMode, string(mode), string(QuietModeOff), string(QuietModeOn))
+	}
+
+	return c.UpdateConfigContext(ctx, deviceName, ConfigUpdate{
+		Sentio: SentioUpdate{
+			QuietSettings: &QuietSettings{Mode: mode},
+		},
+	})
+}
+
+// updateRoom applies a partial update to a room and returns the updated room.
+func (c *Client) updateRoom(ctx context.Context, deviceName string, update RoomUpdate) (UpdatedRoom, error) {
+	updated, err := c.UpdateConfigContext(ctx, deviceName, ConfigUpdate{
+		Sentio: SentioUpdate{Rooms: []RoomUpdate{update}},
+	})
+	if err != nil {
+		return UpdatedRoom{}, err
 	}
-	jsonReq, err := json.Marshal(r)
+
+	room, ok := updated.Room(update.ID)
+	if !ok {
+		return UpdatedRoom{}, fmt.Errorf("%w: %s", ErrRoomNotFound, update.ID)
+	}
+
+	return UpdatedRoom{Room: room, HcMode: updated.LastConfig.Sentio.HcMode}, nil
+}
+
+// updateRooms applies partial updates to rooms with a single request,
+// and returns the updated rooms.
+func (c *Client) updateRooms(ctx context.Context, deviceName string, updates []RoomUpdate) ([]Room, error) {
+	updated, err := c.UpdateConfigContext(ctx, deviceName, ConfigUpdate{
+		Sentio: SentioUpdate{Rooms: updates},
+	})
 	if err != nil {
-		return Device{}, err
+		return nil, err
+	}
+
+	result := make([]Room, 0, len(updates))
+	for _, update := range updates {
+		room, ok := updated.Room(update.ID)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrRoomNotFound, update.ID)
+		}
+		result = append(result, room)
 	}
 
-	req, err := http.NewRequest("POST", c.endpoint+"/GetDevice", strings.NewReader(string(jsonReq)))
+	return result, nil
+}
+
+// call invokes a BlazeDeviceService method, sending in as the JSON request
+// body and decoding the JSON response body into out.
+//
+// If the token is rejected, call forces a token refresh and tries once
+// more. Idempotent methods are also retried with backoff after transient
+// network errors and 429 or 5xx responses.
+func (c *Client) call(ctx context.Context, method string, in, out any) error {
+	jsonReq, err := json.Marshal(in)
 	if err != nil {
-		return Device{}, err
+		return err
+	}
+
+	// Re-authenticating is allowed once per call, and does not count
+	// as a retry.
+	reauthenticated := false
+	for attempt := 0; ; {
+		err := c.do(ctx, method, jsonReq, out)
+		if err == nil {
+			return nil
+		}
+
+		if IsUnauthenticated(err) && !reauthenticated {
+			// The token may have been revoked server-side before
+			// its expiration, so we get a new one.
+			if _, refreshErr := c.identity.RefreshTokenContext(ctx); refreshErr != nil {
+				return errors.Join(err, refreshErr)
+			}
+			reauthenticated = true
+			continue
+		}
+
+		if !idempotentMethods[method] || attempt >= c.maxRetries || !isRetryable(ctx, err) {
+			return err
+		}
+
+		delay := c.backoff(attempt)
+		c.logger.WarnContext(ctx, "retrying request",
+			slog.String("method", method),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay),
+			slog.Any("error", err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		attempt++
+	}
+}
+
+// do sends a single request to a BlazeDeviceService method.
+func (c *Client) do(ctx context.Context, method string, body []byte, out any) error {
+	token, err := c.identity.GetTokenContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
 	}
 
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Authorization", "Bearer "+token.ID)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 
+	start := time.Now()
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return Device{}, err
+		c.logger.DebugContext(ctx, "request failed", slog.String("method", method), slog.Any("error", err))
+		return err
 	}
 	defer resp.Body.Close() // nolint
 
+	c.logger.DebugContext(ctx, "request completed",
+		slog.String("method", method),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("duration", time.Since(start)),
+	)
+
 	if resp.StatusCode != http.StatusOK {
-		return Device{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return newAPIError(resp)
 	}
 
-	var device Device
-	if err := json.NewDecoder(resp.Body).Decode(&device); err != nil {
-		return Device{}, err
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// backoff returns the delay before the given retry attempt, doubling
+// at each attempt with a random jitter.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.maxBackoff
+	if attempt < 32 {
+		delay = min(c.minBackoff<<attempt, c.maxBackoff)
+	}
+	if delay <= 0 {
+		return 0
 	}
 
-	return device, nil
+	// Wait between half and the full delay.
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// isRetryable reports whether a failed request can be sent again.
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+
+	// Timeouts and dropped connections are transient, while other
+	// errors, like DNS or TLS failures, fail again on the next attempt.
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
 }
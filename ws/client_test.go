@@ -0,0 +1,398 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/zmoog/ws/v2/ws/identity"
+)
+
+type staticIdentity struct{}
+
+func (staticIdentity) GetToken() (identity.Token, error) {
+	return identity.Token{ID: "test-token"}, nil
+}
+
+func (staticIdentity) GetTokenContext(ctx context.Context) (identity.Token, error) {
+	return identity.Token{ID: "test-token"}, nil
+}
+
+func (staticIdentity) RefreshToken() (identity.Token, error) {
+	return identity.Token{ID: "test-token"}, nil
+}
+
+func (staticIdentity) RefreshTokenContext(ctx context.Context) (identity.Token, error) {
+	return identity.Token{ID: "test-token"}, nil
+}
+
+// rotatingIdentity returns a new token after each refresh.
+type rotatingIdentity struct {
+	refreshes int
+}
+
+func (r *rotatingIdentity) GetToken() (identity.Token, error) {
+	return r.GetTokenContext(context.Background())
+}
+
+func (r *rotatingIdentity) GetTokenContext(ctx context.Context) (identity.Token, error) {
+	return identity.Token{ID: fmt.Sprintf("token-%d", r.refreshes)}, nil
+}
+
+func (r *rotatingIdentity) RefreshToken() (identity.Token, error) {
+	return r.RefreshTokenContext(context.Background())
+}
+
+func (r *rotatingIdentity) RefreshTokenContext(ctx context.Context) (identity.Token, error) {
+	r.refreshes++
+	return r.GetTokenContext(ctx)
+}
+
+func newTestDevice() Device {
+	return Device{
+		Name: "devices/test",
+		LastConfig: LastConfig{
+			Sentio: Sentio{
+				Rooms: []Room{
+					{
+						ID:                     "room-1",
+						Title:                  "Kitchen",
+						SetpointTemperature:    20,
+						MinSetpointTemperature: 6,
+						MaxSetpointTemperature: 30,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestClient_SetRoomTemperature(t *testing.T) {
+	// Arrange
+	device := newTestDevice()
+	device.LastConfig.Sentio.HcMode = HcModeCooling
+
+	var update ConfigUpdate
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/GetDevice":
+			_ = json.NewEncoder(w).Encode(device)
+		case "/UpdateConfig":
+			var req struct {
+				Name   string       `json:"name"`
+				Config ConfigUpdate `json:"config"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+			update = req.Config
+			device.LastConfig.Sentio.Rooms[0].SetpointTemperature = *req.Config.Sentio.Rooms[0].SetpointTemperature
+			_ = json.NewEncoder(w).Encode(device)
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(staticIdentity{}, server.URL)
+
+	// Act
+	room, err := client.SetRoomTemperature("devices/test", "kitchen", 21.5)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if room.SetpointTemperature != 21.5 {
+		t.Errorf("Expected setpoint 21.5, got %.1f", room.SetpointTemperature)
+	}
+	if room.HcMode != HcModeCooling {
+		t.Errorf("Expected heating/cooling mode %s, got %s", HcModeCooling, room.HcMode)
+	}
+	if len(update.Sentio.Rooms) != 1 || update.Sentio.Rooms[0].ID != "room-1" {
+		t.Errorf("Expected update for room-1, got %+v", update.Sentio.Rooms)
+	}
+}
+
+func TestClient_SetRoomTemperature_OutOfRange(t *testing.T) {
+	// Arrange
+	device := newTestDevice()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/GetDevice" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(device)
+	}))
+	defer server.Close()
+
+	client := NewClient(staticIdentity{}, server.URL)
+
+	// Act
+	_, err := client.SetRoomTemperature("devices/test", "room-1", 35)
+
+	// Assert
+	if !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestClient_SetRoomTemperature_RoomNotFound(t *testing.T) {
+	// Arrange
+	device := newTestDevice()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(device)
+	}))
+	defer server.Close()
+
+	client := NewClient(staticIdentity{}, server.URL)
+
+	// Act
+	_, err := client.SetRoomTemperature("devices/test", "Garage", 21)
+
+	// Assert
+	if !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound, got %v", err)
+	}
+}
+
+func TestClient_ReauthenticatesOnUnauthenticated(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"unauthenticated","message":"token revoked"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Devices{Devices: []Device{{Name: "devices/test"}}})
+	}))
+	defer server.Close()
+
+	im := &rotatingIdentity{}
+	client := NewClient(im, server.URL)
+
+	// Act
+	devices, err := client.ListDevices()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(devices) != 1 {
+		t.Errorf("Expected 1 device, got %d", len(devices))
+	}
+	if im.refreshes != 1 {
+		t.Errorf("Expected 1 token refresh, got %d", im.refreshes)
+	}
+}
+
+func TestClient_RetriesIdempotentRequests(t *testing.T) {
+	// Arrange
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(newTestDevice())
+	}))
+	defer server.Close()
+
+	client := NewClient(staticIdentity{}, server.URL, WithMaxRetries(2), WithBackoff(time.Millisecond, time.Millisecond))
+
+	// Act
+	_, err := client.GetDevice("devices/test")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+}
+
+func TestClient_DoesNotRetryWrites(t *testing.T) {
+	// Arrange
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(staticIdentity{}, server.URL, WithMaxRetries(2), WithBackoff(time.Millisecond, time.Millisecond))
+
+	// Act
+	_, err := client.UpdateConfig("devices/test", ConfigUpdate{})
+
+	// Assert
+	if !IsUnavailable(err) {
+		t.Errorf("Expected unavailable error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+func TestClient_ReauthenticationIsNotARetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		failures   []int
+		requests   int
+	}{
+		{"reauthenticate without retries", 0, []int{http.StatusUnauthorized}, 2},
+		{"retry after reauthenticating", 1, []int{http.StatusUnauthorized, http.StatusServiceUnavailable}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= len(tt.failures) {
+					w.WriteHeader(tt.failures[requests-1])
+					return
+				}
+				_ = json.NewEncoder(w).Encode(newTestDevice())
+			}))
+			defer server.Close()
+
+			client := NewClient(&rotatingIdentity{}, server.URL, WithMaxRetries(tt.maxRetries), WithBackoff(time.Millisecond, time.Millisecond))
+
+			// Act
+			_, err := client.GetDevice("devices/test")
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if requests != tt.requests {
+				t.Errorf("Expected %d requests, got %d", tt.requests, requests)
+			}
+		})
+	}
+}
+
+func TestClient_RetriesTransientNetworkErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		requests int
+	}{
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, 3},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, 3},
+		{"unexpected EOF", io.ErrUnexpectedEOF, 3},
+		{"timeout", &net.DNSError{Err: "i/o timeout", Name: "api.example", IsTimeout: true}, 3},
+		{"unknown host", &net.DNSError{Err: "no such host", Name: "api.example", IsNotFound: true}, 1},
+		{"TLS failure", errors.New("tls: failed to verify certificate"), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			requests := 0
+			failing := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				requests++
+				return nil, tt.err
+			})
+
+			client := NewClient(
+				staticIdentity{},
+				"http://api.example",
+				WithHTTPClient(&http.Client{Transport: failing}),
+				WithMaxRetries(2),
+				WithBackoff(time.Millisecond, time.Millisecond),
+			)
+
+			// Act
+			_, err := client.GetDevice("devices/test")
+
+			// Assert
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+			if requests != tt.requests {
+				t.Errorf("Expected %d requests, got %d", tt.requests, requests)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClient_Options(t *testing.T) {
+	// Arrange
+	var userAgent, traceID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		traceID = r.Header.Get("X-Trace-Id")
+		_ = json.NewEncoder(w).Encode(Devices{})
+	}))
+	defer server.Close()
+
+	tracing := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Trace-Id", "trace-1")
+			return next.RoundTrip(req)
+		})
+	}
+
+	client := NewClient(
+		staticIdentity{},
+		"http://invalid.example",
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithUserAgent("ws-test/1.0"),
+		WithTransportMiddleware(tracing),
+	)
+
+	// Act
+	_, err := client.ListDevices()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if userAgent != "ws-test/1.0" {
+		t.Errorf("Expected user agent ws-test/1.0, got %q", userAgent)
+	}
+	if traceID != "trace-1" {
+		t.Errorf("Expected trace ID header from middleware, got %q", traceID)
+	}
+}
+
+func TestClient_NilLogger(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(staticIdentity{}, server.URL, WithLogger(nil), WithBackoff(time.Millisecond, time.Millisecond))
+
+	// Act
+	_, err := client.ListDevices()
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+}
@@ -0,0 +1,243 @@
+package ws_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zmoog/ws/v2/ws"
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+func TestClient_Updates(t *testing.T) {
+	until := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		update  func(client *ws.Client) error
+		err     error
+		updates int
+		assert  func(t *testing.T, sentio ws.Sentio)
+	}{
+		{
+			name: "start vacation",
+			update: func(client *ws.Client) error {
+				_, err := client.StartVacation("devices/home", until)
+				return err
+			},
+			updates: 1,
+			assert: func(t *testing.T, sentio ws.Sentio) {
+				vacation := sentio.VacationSettings
+				if vacation.VacationMode != ws.VacationModeOn || !vacation.VacationModeUntil.Equal(until) {
+					t.Errorf("Expected vacation mode on until %s, got %+v", until, vacation)
+				}
+			},
+		},
+		{
+			name: "start vacation in the past",
+			update: func(client *ws.Client) error {
+				_, err := client.StartVacation("devices/home", time.Now().Add(-time.Hour))
+				return err
+			},
+			err: ws.ErrVacationEnd,
+		},
+		{
+			name: "stop vacation",
+			update: func(client *ws.Client) error {
+				if _, err := client.StartVacation("devices/home", until); err != nil {
+					return err
+				}
+				_, err := client.StopVacation("devices/home")
+				return err
+			},
+			updates: 2,
+			assert: func(t *testing.T, sentio ws.Sentio) {
+				if vacation := sentio.VacationSettings; vacation.VacationMode != ws.VacationModeOff {
+					t.Errorf("Expected vacation mode off, got %+v", vacation)
+				}
+			},
+		},
+		{
+			name: "set heating/cooling mode",
+			update: func(client *ws.Client) error {
+				_, err := client.SetHcMode("devices/home", ws.HcModeCooling)
+				return err
+			},
+			updates: 1,
+			assert: func(t *testing.T, sentio ws.Sentio) {
+				if sentio.HcMode != ws.HcModeCooling {
+					t.Errorf("Expected cooling mode, got %s", sentio.HcMode)
+				}
+			},
+		},
+		{
+			name: "set unavailable heating/cooling mode",
+			update: func(client *ws.Client) error {
+				_, err := client.SetHcMode("devices/home", "HC_MODE_AUTO")
+				return err
+			},
+			err: ws.ErrHcModeNotAvailable,
+		},
+		{
+			name: "set standby mode",
+			update: func(client *ws.Client) error {
+				_, err := client.SetStandbyMode("devices/home", ws.StandbyModeOn)
+				return err
+			},
+			updates: 1,
+			assert: func(t *testing.T, sentio ws.Sentio) {
+				if sentio.StandbyMode != ws.StandbyModeOn {
+					t.Errorf("Expected standby mode on, got %s", sentio.StandbyMode)
+				}
+				if sentio.HcMode != ws.HcModeHeating {
+					t.Errorf("Expected the heating/cooling mode to be unchanged, got %s", sentio.HcMode)
+				}
+			},
+		},
+		{
+			name: "lock rooms",
+			update: func(client *ws.Client) error {
+				_, err := client.SetLockMode("devices/home", ws.LockModeLocked, "Kitchen", "room-2")
+				return err
+			},
+			updates: 1,
+			assert: func(t *testing.T, sentio ws.Sentio) {
+				for _, room := range sentio.Rooms {
+					if room.LockMode != ws.LockModeLocked {
+						t.Errorf("Expected %s to be locked, got %s", room.ID, room.LockMode)
+					}
+				}
+			},
+		},
+		{
+			name: "lock unknown room",
+			update: func(client *ws.Client) error {
+				_, err := client.SetLockMode("devices/home", ws.LockModeLocked, "Kitchen", "Garage")
+				return err
+			},
+			err: ws.ErrRoomNotFound,
+		},
+		{
+			name: "set humidity without a preset for the mode",
+			update: func(client *ws.Client) error {
+				_, err := client.SetRoomHumidity("devices/home", "Kitchen", 50)
+				return err
+			},
+			err: ws.ErrNoDehumidificationPreset,
+		},
+		{
+			name: "set humidity out of range",
+			update: func(client *ws.Client) error {
+				if _, err := client.SetHcMode("devices/home", ws.HcModeCooling); err != nil {
+					return err
+				}
+				_, err := client.SetRoomHumidity("devices/home", "Kitchen", 95)
+				return err
+			},
+			err:     ws.ErrOutOfRange,
+			updates: 1,
+		},
+		{
+			name: "set humidity",
+			update: func(client *ws.Client) error {
+				if _, err := client.SetHcMode("devices/home", ws.HcModeCooling); err != nil {
+					return err
+				}
+				_, err := client.SetRoomHumidity("devices/home", "Kitchen", 50)
+				return err
+			},
+			updates: 2,
+			assert: func(t *testing.T, sentio ws.Sentio) {
+				if preset, _ := sentio.Rooms[0].DehumidificationPreset(ws.HcModeCooling); preset.Setpoint != 50 {
+					t.Errorf("Expected humidity setpoint 50, got %.1f", preset.Setpoint)
+				}
+			},
+		},
+		{
+			name: "set preset temperature",
+			update: func(client *ws.Client) error {
+				_, err := client.SetPresetTemperature("devices/home", ws.PresetTypeEco, 17.5, "Kitchen", "Bedroom")
+				return err
+			},
+			updates: 1,
+			assert: func(t *testing.T, sentio ws.Sentio) {
+				for _, room := range sentio.Rooms {
+					if preset, _ := room.TemperaturePreset(ws.PresetTypeEco, ws.HcModeHeating); preset.SetpointTemperature != 17.5 {
+						t.Errorf("Expected eco preset 17.5 in %s, got %.1f", room.Title, preset.SetpointTemperature)
+					}
+				}
+			},
+		},
+		{
+			name: "set preset temperature out of range",
+			update: func(client *ws.Client) error {
+				_, err := client.SetPresetTemperature("devices/home", ws.PresetTypeEco, 45, "Kitchen")
+				return err
+			},
+			err: ws.ErrOutOfRange,
+		},
+		{
+			name: "activate preset",
+			update: func(client *ws.Client) error {
+				if _, err := client.SetPresetTemperature("devices/home", ws.PresetTypeEco, 17.5, "Kitchen"); err != nil {
+					return err
+				}
+				_, err := client.ActivatePreset("devices/home", "Kitchen", ws.PresetTypeEco)
+				return err
+			},
+			updates: 2,
+			assert: func(t *testing.T, sentio ws.Sentio) {
+				if room := sentio.Rooms[0]; room.SetpointTemperature != 17.5 {
+					t.Errorf("Expected setpoint 17.5 after activating the eco preset, got %.1f", room.SetpointTemperature)
+				}
+			},
+		},
+		{
+			name: "set quiet mode",
+			update: func(client *ws.Client) error {
+				_, err := client.SetQuietMode("devices/home", ws.QuietModeOn)
+				return err
+			},
+			updates: 1,
+			assert: func(t *testing.T, sentio ws.Sentio) {
+				if sentio.QuietSettings.Mode != ws.QuietModeOn {
+					t.Errorf("Expected quiet mode on, got %s", sentio.QuietSettings.Mode)
+				}
+			},
+		},
+		{
+			name: "set invalid quiet mode",
+			update: func(client *ws.Client) error {
+				_, err := client.SetQuietMode("devices/home", "QUIET_MODE_LOUD")
+				return err
+			},
+			err: ws.ErrInvalidQuietMode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := wstest.NewServer(wstest.NewDevice("devices/home",
+				wstest.NewRoom("room-1", "Kitchen"),
+				wstest.NewRoom("room-2", "Bedroom"),
+			))
+			defer server.Close()
+
+			// Act
+			err := tt.update(server.Client())
+
+			// Assert
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected %v, got %v", tt.err, err)
+			}
+			if got := server.Requests("UpdateConfig"); got != tt.updates {
+				t.Errorf("Expected %d UpdateConfig requests, got %d", tt.updates, got)
+			}
+			if tt.assert != nil {
+				device, _ := server.Device("devices/home")
+				tt.assert(t, device.LastConfig.Sentio)
+			}
+		})
+	}
+}
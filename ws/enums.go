@@ -0,0 +1,265 @@
+package ws
+
+import "strings"
+
+// The enum types below are string types holding the values used by the
+// API, e.g. "TEMPERATURE_STATE_IDLE", so unknown values survive a JSON
+// round trip. Their String method returns a human-friendly label.
+
+// label returns a human-friendly label for an unknown enum value,
+// e.g. "Blocked" for "TEMPERATURE_STATE_BLOCKED".
+func label(value, prefix string) string {
+	s := strings.ReplaceAll(strings.ToLower(strings.TrimPrefix(value, prefix)), "_", " ")
+	if s == "" {
+		return ""
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// PresetType is the type of a temperature preset.
+type PresetType string
+
+const (
+	PresetTypeEco          PresetType = "PRESET_TYPE_ECO"
+	PresetTypeComfort      PresetType = "PRESET_TYPE_COMFORT"
+	PresetTypeExtraComfort PresetType = "PRESET_TYPE_EXTRA_COMFORT"
+)
+
+// IsKnown reports whether the preset type is one of the known constants.
+func (p PresetType) IsKnown() bool {
+	switch p {
+	case PresetTypeEco, PresetTypeComfort, PresetTypeExtraComfort:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p PresetType) String() string {
+	switch p {
+	case PresetTypeEco:
+		return "Eco"
+	case PresetTypeComfort:
+		return "Comfort"
+	case PresetTypeExtraComfort:
+		return "Extra comfort"
+	default:
+		return label(string(p), "PRESET_TYPE_")
+	}
+}
+
+// ScheduleMode is whether the weekly schedule of a room is enabled.
+type ScheduleMode string
+
+const (
+	ScheduleModeOff ScheduleMode = "SCHEDULE_MODE_OFF"
+	ScheduleModeOn  ScheduleMode = "SCHEDULE_MODE_ON"
+)
+
+// IsKnown reports whether the schedule mode is one of the known constants.
+func (m ScheduleMode) IsKnown() bool {
+	switch m {
+	case ScheduleModeOff, ScheduleModeOn:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m ScheduleMode) String() string {
+	switch m {
+	case ScheduleModeOff:
+		return "Off"
+	case ScheduleModeOn:
+		return "On"
+	default:
+		return label(string(m), "SCHEDULE_MODE_")
+	}
+}
+
+// Day is a day of the week in a weekly schedule, as returned by the API.
+type Day string
+
+func (d Day) String() string {
+	return label(string(d), "DAY_")
+}
+
+// VacationMode is the vacation mode of a device or a room.
+type VacationMode string
+
+const (
+	VacationModeOff VacationMode = "VACATION_MODE_OFF"
+	VacationModeOn  VacationMode = "VACATION_MODE_ON"
+)
+
+func (v VacationMode) String() string {
+	switch v {
+	case VacationModeOff:
+		return "Off"
+	case VacationModeOn:
+		return "On"
+	default:
+		return label(string(v), "VACATION_MODE_")
+	}
+}
+
+// HcMode is the heating or cooling mode of a device.
+type HcMode string
+
+const (
+	HcModeHeating HcMode = "HC_MODE_HEATING"
+	HcModeCooling HcMode = "HC_MODE_COOLING"
+)
+
+func (m HcMode) String() string {
+	switch m {
+	case HcModeHeating:
+		return "Heating"
+	case HcModeCooling:
+		return "Cooling"
+	default:
+		return label(string(m), "HC_MODE_")
+	}
+}
+
+// AutomaticHcMode is whether a device switches automatically between
+// heating and cooling.
+type AutomaticHcMode string
+
+const (
+	AutomaticHcModeOff AutomaticHcMode = "AUTOMATIC_HC_MODE_OFF"
+	AutomaticHcModeOn  AutomaticHcMode = "AUTOMATIC_HC_MODE_ON"
+)
+
+func (m AutomaticHcMode) String() string {
+	switch m {
+	case AutomaticHcModeOff:
+		return "Off"
+	case AutomaticHcModeOn:
+		return "On"
+	default:
+		return label(string(m), "AUTOMATIC_HC_MODE_")
+	}
+}
+
+// StandbyMode is the standby mode of a device.
+type StandbyMode string
+
+const (
+	StandbyModeOff StandbyMode = "STANDBY_MODE_OFF"
+	StandbyModeOn  StandbyMode = "STANDBY_MODE_ON"
+)
+
+func (m StandbyMode) String() string {
+	switch m {
+	case StandbyModeOff:
+		return "Off"
+	case StandbyModeOn:
+		return "On"
+	default:
+		return label(string(m), "STANDBY_MODE_")
+	}
+}
+
+// LockMode is the lock mode of the thermostat in a room.
+type LockMode string
+
+const (
+	LockModeUnlocked LockMode = "LOCK_MODE_UNLOCKED"
+	LockModeLocked   LockMode = "LOCK_MODE_LOCKED"
+)
+
+func (m LockMode) String() string {
+	switch m {
+	case LockModeUnlocked:
+		return "Unlocked"
+	case LockModeLocked:
+		return "Locked"
+	default:
+		return label(string(m), "LOCK_MODE_")
+	}
+}
+
+// QuietMode is the quiet mode of a device, reducing the noise
+// of the heat pump, e.g. at night.
+type QuietMode string
+
+const (
+	QuietModeOff QuietMode = "QUIET_MODE_OFF"
+	QuietModeOn  QuietMode = "QUIET_MODE_ON"
+)
+
+// QuietModes are the valid quiet modes.
+var QuietModes = []QuietMode{
+	QuietModeOff,
+	QuietModeOn,
+}
+
+func (m QuietMode) String() string {
+	switch m {
+	case QuietModeOff:
+		return "Off"
+	case QuietModeOn:
+		return "On"
+	default:
+		return label(string(m), "QUIET_MODE_")
+	}
+}
+
+// TemperatureState is the heating or cooling state of a room.
+type TemperatureState string
+
+const (
+	TemperatureStateIdle    TemperatureState = "TEMPERATURE_STATE_IDLE"
+	TemperatureStateHeating TemperatureState = "TEMPERATURE_STATE_HEATING"
+	TemperatureStateCooling TemperatureState = "TEMPERATURE_STATE_COOLING"
+)
+
+func (s TemperatureState) String() string {
+	switch s {
+	case TemperatureStateIdle:
+		return "Idle"
+	case TemperatureStateHeating:
+		return "Heating"
+	case TemperatureStateCooling:
+		return "Cooling"
+	default:
+		return label(string(s), "TEMPERATURE_STATE_")
+	}
+}
+
+// DehumidifierState is the state of the dehumidifier of a room.
+type DehumidifierState string
+
+const (
+	DehumidifierStateIdle          DehumidifierState = "DEHUMIDIFIER_STATE_IDLE"
+	DehumidifierStateDehumidifying DehumidifierState = "DEHUMIDIFIER_STATE_DEHUMIDIFYING"
+)
+
+func (s DehumidifierState) String() string {
+	switch s {
+	case DehumidifierStateIdle:
+		return "Idle"
+	case DehumidifierStateDehumidifying:
+		return "Dehumidifying"
+	default:
+		return label(string(s), "DEHUMIDIFIER_STATE_")
+	}
+}
+
+// DeviceType is the type of a device.
+type DeviceType string
+
+const (
+	DeviceTypeSentioCCU DeviceType = "TYPE_SENTIO_CCU"
+)
+
+func (t DeviceType) String() string {
+	switch t {
+	case DeviceTypeSentioCCU:
+		return "Sentio CCU"
+	default:
+		return label(string(t), "TYPE_")
+	}
+}
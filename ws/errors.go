@@ -0,0 +1,180 @@
+package ws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Code is an error code returned by the BlazeDeviceService.
+//
+// The codes follow the Connect protocol, which uses the same set
+// of codes as gRPC in their snake case form.
+type Code string
+
+const (
+	CodeCanceled           Code = "canceled"
+	CodeUnknown            Code = "unknown"
+	CodeInvalidArgument    Code = "invalid_argument"
+	CodeDeadlineExceeded   Code = "deadline_exceeded"
+	CodeNotFound           Code = "not_found"
+	CodeAlreadyExists      Code = "already_exists"
+	CodePermissionDenied   Code = "permission_denied"
+	CodeResourceExhausted  Code = "resource_exhausted"
+	CodeFailedPrecondition Code = "failed_precondition"
+	CodeAborted            Code = "aborted"
+	CodeOutOfRange         Code = "out_of_range"
+	CodeUnimplemented      Code = "unimplemented"
+	CodeInternal           Code = "internal"
+	CodeUnavailable        Code = "unavailable"
+	CodeDataLoss           Code = "data_loss"
+	CodeUnauthenticated    Code = "unauthenticated"
+)
+
+// grpcCodes maps the numeric gRPC codes to their Connect names.
+var grpcCodes = []Code{
+	"ok",
+	CodeCanceled,
+	CodeUnknown,
+	CodeInvalidArgument,
+	CodeDeadlineExceeded,
+	CodeNotFound,
+	CodeAlreadyExists,
+	CodePermissionDenied,
+	CodeResourceExhausted,
+	CodeFailedPrecondition,
+	CodeAborted,
+	CodeOutOfRange,
+	CodeUnimplemented,
+	CodeInternal,
+	CodeUnavailable,
+	CodeDataLoss,
+	CodeUnauthenticated,
+}
+
+// UnmarshalJSON decodes both the Connect form of the code ("not_found")
+// and the gRPC forms ("NOT_FOUND" or 5).
+func (c *Code) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		if number < 0 || number >= len(grpcCodes) {
+			*c = CodeUnknown
+			return nil
+		}
+		*c = grpcCodes[number]
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	*c = Code(strings.ToLower(name))
+
+	return nil
+}
+
+// APIError is an error returned by the BlazeDeviceService.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+
+	Code    Code          `json:"code"`
+	Message string        `json:"message"`
+	Details []ErrorDetail `json:"details,omitempty"`
+}
+
+// ErrorDetail is an additional detail attached to an APIError.
+type ErrorDetail struct {
+	Type  string          `json:"type"`
+	Value string          `json:"value"`
+	Debug json.RawMessage `json:"debug,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s (status code %d)", e.Code, e.StatusCode)
+	}
+	return fmt.Sprintf("%s: %s (status code %d)", e.Code, e.Message, e.StatusCode)
+}
+
+// newAPIError builds an APIError from an unsuccessful response.
+//
+// When the response body does not contain a valid error, the code
+// is derived from the HTTP status code.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	body, err := io.ReadAll(resp.Body)
+	if err == nil && json.Unmarshal(body, apiErr) == nil && apiErr.Code != "" {
+		return apiErr
+	}
+
+	apiErr.Code = codeFromStatus(resp.StatusCode)
+	apiErr.Message = strings.TrimSpace(string(body))
+
+	return apiErr
+}
+
+// codeFromStatus returns the error code that best matches an
+// HTTP status code.
+func codeFromStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidArgument
+	case http.StatusUnauthorized:
+		return CodeUnauthenticated
+	case http.StatusForbidden:
+		return CodePermissionDenied
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeAborted
+	case http.StatusTooManyRequests:
+		return CodeResourceExhausted
+	case http.StatusInternalServerError:
+		return CodeInternal
+	case http.StatusNotImplemented:
+		return CodeUnimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return CodeUnavailable
+	default:
+		return CodeUnknown
+	}
+}
+
+// ErrorCode returns the code of the APIError in the chain of err,
+// or an empty code if there is none.
+func ErrorCode(err error) Code {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return ""
+}
+
+// IsNotFound reports whether err is an APIError for a missing resource.
+func IsNotFound(err error) bool {
+	return ErrorCode(err) == CodeNotFound
+}
+
+// IsUnauthenticated reports whether err is an APIError for a missing,
+// invalid or expired token.
+func IsUnauthenticated(err error) bool {
+	return ErrorCode(err) == CodeUnauthenticated
+}
+
+// IsPermissionDenied reports whether err is an APIError for a resource
+// the account is not allowed to access.
+func IsPermissionDenied(err error) bool {
+	return ErrorCode(err) == CodePermissionDenied
+}
+
+// IsUnavailable reports whether err is an APIError for a service
+// that is temporarily unavailable.
+func IsUnavailable(err error) bool {
+	return ErrorCode(err) == CodeUnavailable
+}
@@ -0,0 +1,83 @@
+package ws
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newErrorResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestNewAPIError_ConnectBody(t *testing.T) {
+	// Arrange
+	resp := newErrorResponse(http.StatusNotFound, `{"code":"not_found","message":"device not found","details":[{"type":"google.rpc.ResourceInfo","value":"Cg=="}]}`)
+
+	// Act
+	err := newAPIError(resp)
+
+	// Assert
+	if err.Code != CodeNotFound {
+		t.Errorf("Expected code %s, got %s", CodeNotFound, err.Code)
+	}
+	if err.Message != "device not found" {
+		t.Errorf("Expected message 'device not found', got %q", err.Message)
+	}
+	if len(err.Details) != 1 || err.Details[0].Type != "google.rpc.ResourceInfo" {
+		t.Errorf("Expected one ResourceInfo detail, got %+v", err.Details)
+	}
+	if !IsNotFound(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("Expected IsNotFound to match the wrapped error")
+	}
+}
+
+func TestNewAPIError_GRPCBody(t *testing.T) {
+	tests := []struct {
+		body string
+		want Code
+	}{
+		{`{"code":16,"message":"token expired"}`, CodeUnauthenticated},
+		{`{"code":"PERMISSION_DENIED","message":"forbidden"}`, CodePermissionDenied},
+		{`{"code":99}`, CodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			err := newAPIError(newErrorResponse(http.StatusBadRequest, tt.body))
+			if err.Code != tt.want {
+				t.Errorf("Expected code %s, got %s", tt.want, err.Code)
+			}
+		})
+	}
+}
+
+func TestNewAPIError_StatusFallback(t *testing.T) {
+	tests := []struct {
+		status int
+		want   Code
+	}{
+		{http.StatusUnauthorized, CodeUnauthenticated},
+		{http.StatusForbidden, CodePermissionDenied},
+		{http.StatusNotFound, CodeNotFound},
+		{http.StatusServiceUnavailable, CodeUnavailable},
+		{http.StatusTeapot, CodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			err := newAPIError(newErrorResponse(tt.status, "upstream connect error"))
+			if err.Code != tt.want {
+				t.Errorf("Expected code %s, got %s", tt.want, err.Code)
+			}
+			if err.Message != "upstream connect error" {
+				t.Errorf("Expected the body as message, got %q", err.Message)
+			}
+		})
+	}
+}
@@ -5,4 +5,11 @@ type Config struct {
 	Username  string
 	Password  string
 	WebApiKey string
+
+	// SignInEndpoint overrides DefaultSignInEndpoint, for example
+	// to use the Firebase Auth emulator.
+	SignInEndpoint string
+	// TokenEndpoint overrides DefaultTokenEndpoint, for example
+	// to use the Firebase Auth emulator.
+	TokenEndpoint string
 }
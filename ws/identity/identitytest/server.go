@@ -0,0 +1,255 @@
+// Package identitytest provides a fake Firebase identity server
+// for testing code that uses the identity package.
+package identitytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+
+	"github.com/zmoog/ws/v2/ws/identity"
+)
+
+const (
+	// APIKey is the Web API key accepted by the server.
+	APIKey = "test-api-key"
+
+	signInPath = "/identitytoolkit.googleapis.com/v1/accounts:signInWithPassword"
+	tokenPath  = "/securetoken.googleapis.com/v1/token"
+)
+
+// Server is a fake Firebase identity server implementing the sign-in
+// with password and refresh token endpoints.
+//
+// It serves the endpoints on the same paths as the Firebase Auth
+// emulator, so Config returns a configuration pointing to it.
+type Server struct {
+	*httptest.Server
+
+	// ExpiresIn is the lifespan in seconds of the issued tokens.
+	ExpiresIn int
+
+	mu            sync.Mutex
+	users         map[string]string
+	refreshTokens map[string]string
+	issued        int
+	signIns       int
+	refreshes     int
+	failure       *failure
+}
+
+type failure struct {
+	status  int
+	message string
+}
+
+// NewServer starts a fake identity server. The caller should call
+// Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		ExpiresIn:     3600,
+		users:         make(map[string]string),
+		refreshTokens: make(map[string]string),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+signInPath, s.handleSignIn)
+	mux.HandleFunc("POST "+tokenPath, s.handleToken)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// AddUser registers a user that can sign in with email and password.
+func (s *Server) AddUser(email, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[email] = password
+}
+
+// Config returns an identity configuration for the given credentials
+// pointing to the server.
+func (s *Server) Config(email, password string) identity.Config {
+	signIn, token := identity.EmulatorEndpoints(s.URL)
+
+	return identity.Config{
+		Username:       email,
+		Password:       password,
+		WebApiKey:      APIKey,
+		SignInEndpoint: signIn,
+		TokenEndpoint:  token,
+	}
+}
+
+// RevokeRefreshTokens invalidates all the refresh tokens issued so far.
+func (s *Server) RevokeRefreshTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refreshTokens = make(map[string]string)
+}
+
+// FailNext makes the next request fail with the given HTTP status
+// and error message, e.g. "TOO_MANY_ATTEMPTS_TRY_LATER".
+func (s *Server) FailNext(status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failure = &failure{status: status, message: message}
+}
+
+// SignIns returns the number of successful sign-ins with password.
+func (s *Server) SignIns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.signIns
+}
+
+// Refreshes returns the number of successful token refreshes.
+func (s *Server) Refreshes() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.refreshes
+}
+
+func (s *Server) handleSignIn(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.checkRequest(w, r) {
+		return
+	}
+
+	var req struct {
+		Email             string `json:"email"`
+		Password          string `json:"password"`
+		ReturnSecureToken bool   `json:"returnSecureToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON")
+		return
+	}
+
+	password, ok := s.users[req.Email]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "EMAIL_NOT_FOUND")
+		return
+	}
+	if password != req.Password {
+		writeError(w, http.StatusBadRequest, "INVALID_PASSWORD")
+		return
+	}
+
+	idToken, refreshToken := s.issue(req.Email)
+	s.signIns++
+
+	writeJSON(w, map[string]any{
+		"kind":         "identitytoolkit#VerifyPasswordResponse",
+		"localId":      localID(req.Email),
+		"email":        req.Email,
+		"idToken":      idToken,
+		"registered":   true,
+		"refreshToken": refreshToken,
+		"expiresIn":    strconv.Itoa(s.ExpiresIn),
+	})
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.checkRequest(w, r) {
+		return
+	}
+
+	var req struct {
+		GrantType    string `json:"grant_type"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON")
+		return
+	}
+
+	if req.GrantType != "refresh_token" {
+		writeError(w, http.StatusBadRequest, "INVALID_GRANT_TYPE")
+		return
+	}
+
+	email, ok := s.refreshTokens[req.RefreshToken]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "INVALID_REFRESH_TOKEN")
+		return
+	}
+	delete(s.refreshTokens, req.RefreshToken)
+
+	idToken, refreshToken := s.issue(email)
+	s.refreshes++
+
+	writeJSON(w, map[string]any{
+		"access_token":  idToken,
+		"expires_in":    strconv.Itoa(s.ExpiresIn),
+		"token_type":    "Bearer",
+		"refresh_token": refreshToken,
+		"id_token":      idToken,
+		"user_id":       localID(email),
+		"project_id":    "identitytest",
+	})
+}
+
+// checkRequest writes an error response and returns false if the request
+// has an invalid API key or a failure has been injected.
+func (s *Server) checkRequest(w http.ResponseWriter, r *http.Request) bool {
+	if f := s.failure; f != nil {
+		s.failure = nil
+		writeError(w, f.status, f.message)
+		return false
+	}
+
+	if r.URL.Query().Get("key") != APIKey {
+		writeError(w, http.StatusBadRequest, "API key not valid. Please pass a valid API key.")
+		return false
+	}
+
+	return true
+}
+
+// issue creates a new ID token and refresh token for the user.
+func (s *Server) issue(email string) (idToken, refreshToken string) {
+	s.issued++
+	idToken = fmt.Sprintf("id-token-%d", s.issued)
+	refreshToken = fmt.Sprintf("refresh-token-%d", s.issued)
+	s.refreshTokens[refreshToken] = email
+
+	return idToken, refreshToken
+}
+
+func localID(email string) string {
+	return fmt.Sprintf("user-%x", email)
+}
+
+// writeError writes an error in the format used by the Firebase APIs.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    status,
+			"message": message,
+			"errors": []map[string]string{
+				{"message": message, "domain": "global", "reason": "invalid"},
+			},
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
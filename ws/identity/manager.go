@@ -1,11 +1,15 @@
 package identity
 
 import (
-	"net/http"
+	"context"
 )
 
+// Manager provides a valid token for the Wavin Sentio API.
 type Manager interface {
 	GetToken() (Token, error)
+	GetTokenContext(ctx context.Context) (Token, error)
+	RefreshToken() (Token, error)
+	RefreshTokenContext(ctx context.Context) (Token, error)
 }
 
 type manager struct {
@@ -13,40 +17,32 @@ type manager struct {
 	storer    Storer
 }
 
-func NewManager(config Config) Manager {
-	retriever := tokenRetriever{
-		httpClient: http.DefaultClient,
-		username:   config.Username,
-		password:   config.Password,
-		webApiKey:  config.WebApiKey,
-	}
-	storer := tokenStorer{}
-
+// NewManager creates a manager that stores the token in the
+// user's home directory.
+func NewManager(config Config, opts ...Option) Manager {
 	return &manager{
-		retriever: &retriever,
-		storer:    &storer,
+		retriever: newTokenRetriever(config, newOptions(opts)),
+		storer:    &tokenStorer{},
 	}
 }
 
 // NewInMemoryManager creates a manager that stores the token in memory.
-func NewInMemoryManager(config Config) Manager {
-	retriever := tokenRetriever{
-		httpClient: http.DefaultClient,
-		username:   config.Username,
-		password:   config.Password,
-		webApiKey:  config.WebApiKey,
-	}
-	storer := NewInMemoryStorer()
-
+func NewInMemoryManager(config Config, opts ...Option) Manager {
 	return &manager{
-		retriever: &retriever,
-		storer:    storer,
+		retriever: newTokenRetriever(config, newOptions(opts)),
+		storer:    NewInMemoryStorer(),
 	}
 }
 
 // GetToken returns a token from the store if it exists and is not expired,
 // otherwise it retrieves a new token.
 func (m *manager) GetToken() (Token, error) {
+	return m.GetTokenContext(context.Background())
+}
+
+// GetTokenContext is like GetToken but uses ctx for the requests
+// to the token endpoints.
+func (m *manager) GetTokenContext(ctx context.Context) (Token, error) {
 	token, exists, err := m.storer.GetToken()
 	if err != nil {
 		return Token{}, err
@@ -57,13 +53,35 @@ func (m *manager) GetToken() (Token, error) {
 		return token, nil
 	}
 
-	// Token is expired or does not exist
+	return m.renewToken(ctx, token, exists)
+}
+
+// RefreshToken retrieves a new token even if the stored one is not
+// expired yet, for example because it has been revoked server-side.
+func (m *manager) RefreshToken() (Token, error) {
+	return m.RefreshTokenContext(context.Background())
+}
+
+// RefreshTokenContext is like RefreshToken but uses ctx for the requests
+// to the token endpoints.
+func (m *manager) RefreshTokenContext(ctx context.Context) (Token, error) {
+	token, exists, err := m.storer.GetToken()
+	if err != nil {
+		return Token{}, err
+	}
+
+	return m.renewToken(ctx, token, exists)
+}
+
+// renewToken retrieves and stores a new token, using the refresh token
+// of the current one when available.
+func (m *manager) renewToken(ctx context.Context, token Token, exists bool) (Token, error) {
 	// Try to refresh first if we have a refresh token
 	if exists && token.RefreshToken != "" {
-		refreshedToken, refreshErr := m.retriever.RefreshToken(token.RefreshToken)
+		refreshedToken, refreshErr := m.retriever.RefreshTokenContext(ctx, token.RefreshToken)
 		if refreshErr == nil {
 			// Successfully refreshed token
-			err = m.storer.StoreToken(refreshedToken)
+			err := m.storer.StoreToken(refreshedToken)
 			if err != nil {
 				return Token{}, err
 			}
@@ -73,7 +91,7 @@ func (m *manager) GetToken() (Token, error) {
 	}
 
 	// Get new token with credentials
-	token, err = m.retriever.GetToken()
+	token, err := m.retriever.GetTokenContext(ctx)
 	if err != nil {
 		return Token{}, err
 	}
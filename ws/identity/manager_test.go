@@ -1,6 +1,7 @@
 package identity
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -16,10 +17,18 @@ func (m *mockRetriever) GetToken() (Token, error) {
 	return m.token, m.err
 }
 
+func (m *mockRetriever) GetTokenContext(ctx context.Context) (Token, error) {
+	return m.token, m.err
+}
+
 func (m *mockRetriever) RefreshToken(refreshToken string) (Token, error) {
 	return m.token, m.err
 }
 
+func (m *mockRetriever) RefreshTokenContext(ctx context.Context, refreshToken string) (Token, error) {
+	return m.token, m.err
+}
+
 type mockStorer struct {
 	token      Token
 	exists     bool
@@ -224,6 +233,47 @@ func TestManager_GetToken_StoreTokenError(t *testing.T) {
 	}
 }
 
+func TestManager_RefreshToken_ValidCachedToken(t *testing.T) {
+	// Arrange
+	validToken := Token{
+		ID:           "revoked-token",
+		RefreshToken: "refresh-token",
+		ExpiresAt:    time.Now().Add(1 * time.Hour),
+	}
+	newToken := Token{
+		ID:        "new-token",
+		ExpiresAt: time.Now().Add(1 * time.Hour),
+	}
+
+	mockStore := &mockStorer{
+		token:  validToken,
+		exists: true,
+	}
+	mockRetriever := &mockRetriever{
+		token: newToken,
+	}
+
+	manager := &manager{
+		retriever: mockRetriever,
+		storer:    mockStore,
+	}
+
+	// Act
+	token, err := manager.RefreshToken()
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if token.ID != newToken.ID {
+		t.Errorf("Expected token ID %s, got %s", newToken.ID, token.ID)
+	}
+	// Verify new token was stored
+	if mockStore.token.ID != newToken.ID {
+		t.Errorf("Expected stored token ID %s, got %s", newToken.ID, mockStore.token.ID)
+	}
+}
+
 func TestNewManager(t *testing.T) {
 	// Act
 	manager := NewManager(Config{
@@ -0,0 +1,75 @@
+package identity
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/zmoog/ws/v2/ws/internal/transport"
+)
+
+// Option configures a Manager.
+type Option func(*options)
+
+type options struct {
+	httpClient  *http.Client
+	userAgent   string
+	logger      *slog.Logger
+	middlewares []transport.Middleware
+}
+
+// WithHTTPClient sets the HTTP client used to send the requests
+// to the Firebase endpoints.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithLogger sets the logger used to report token retrievals.
+// A nil logger discards the records.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		if logger == nil {
+			logger = discardLogger()
+		}
+		o.logger = logger
+	}
+}
+
+// WithTransportMiddleware wraps the transport of the HTTP client.
+//
+// Middlewares are applied in order, so the first one added is the
+// outermost and sees each request first.
+func WithTransportMiddleware(middleware func(http.RoundTripper) http.RoundTripper) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middleware)
+	}
+}
+
+// newOptions applies opts on top of the defaults.
+func newOptions(opts []Option) options {
+	o := options{
+		httpClient: http.DefaultClient,
+		logger:     discardLogger(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	o.httpClient = transport.Wrap(o.httpClient, o.middlewares)
+
+	return o
+}
+
+// discardLogger returns a logger that drops all records.
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
@@ -2,37 +2,102 @@ package identity
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
 //nolint:lll
 const (
-	signInWithPasswordEndpoint = "https://identitytoolkit.googleapis.com/v1/accounts:signInWithPassword?key="
-	tokenEndpoint              = "https://securetoken.googleapis.com/v1/token?key="
-	tokenLifespanPercentage    = 90 // percentage of the token's lifespan to use the calculated expires at value.
+	// DefaultSignInEndpoint is the Firebase endpoint to sign in with email and password.
+	DefaultSignInEndpoint = "https://identitytoolkit.googleapis.com/v1/accounts:signInWithPassword"
+	// DefaultTokenEndpoint is the Firebase endpoint to exchange a refresh token for a new token.
+	DefaultTokenEndpoint = "https://securetoken.googleapis.com/v1/token"
+
+	tokenLifespanPercentage = 90 // percentage of the token's lifespan to use the calculated expires at value.
 )
 
+// EmulatorEndpoints returns the sign-in and token endpoints of a
+// Firebase Auth emulator (or any server using the same paths)
+// listening at baseURL, e.g. "http://localhost:9099".
+func EmulatorEndpoints(baseURL string) (signIn, token string) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return baseURL + "/identitytoolkit.googleapis.com/v1/accounts:signInWithPassword",
+		baseURL + "/securetoken.googleapis.com/v1/token"
+}
+
 // Retriever is an interface for retrieving a token.
 type Retriever interface {
 	GetToken() (Token, error)
+	GetTokenContext(ctx context.Context) (Token, error)
 	RefreshToken(refreshToken string) (Token, error)
+	RefreshTokenContext(ctx context.Context, refreshToken string) (Token, error)
 }
 
 // tokenRetriever is a concrete implementation of Retriever.
 type tokenRetriever struct {
-	httpClient *http.Client
-	webApiKey  string
-	username   string
-	password   string
+	httpClient     *http.Client
+	userAgent      string
+	logger         *slog.Logger
+	signInEndpoint string
+	tokenEndpoint  string
+	webApiKey      string
+	username       string
+	password       string
+}
+
+// newTokenRetriever creates a token retriever for the credentials in config.
+func newTokenRetriever(config Config, o options) *tokenRetriever {
+	r := &tokenRetriever{
+		httpClient:     o.httpClient,
+		userAgent:      o.userAgent,
+		logger:         o.logger,
+		signInEndpoint: config.SignInEndpoint,
+		tokenEndpoint:  config.TokenEndpoint,
+		username:       config.Username,
+		password:       config.Password,
+		webApiKey:      config.WebApiKey,
+	}
+
+	if r.signInEndpoint == "" {
+		r.signInEndpoint = DefaultSignInEndpoint
+	}
+	if r.tokenEndpoint == "" {
+		r.tokenEndpoint = DefaultTokenEndpoint
+	}
+
+	return r
+}
+
+// endpointURL returns the endpoint URL with the Web API key
+// added to the query string.
+func (r *tokenRetriever) endpointURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+
+	query := u.Query()
+	query.Set("key", r.webApiKey)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
 }
 
 // GetToken retrieves a token from the token endpoint.
 func (r *tokenRetriever) GetToken() (Token, error) {
+	return r.GetTokenContext(context.Background())
+}
+
+// GetTokenContext is like GetToken but uses ctx for the request.
+func (r *tokenRetriever) GetTokenContext(ctx context.Context) (Token, error) {
 	req := struct {
 		Email             string `json:"email"`
 		Password          string `json:"password"`
@@ -50,9 +115,15 @@ func (r *tokenRetriever) GetToken() (Token, error) {
 		return Token{}, err
 	}
 
-	request, err := http.NewRequest(
+	endpoint, err := r.endpointURL(r.signInEndpoint)
+	if err != nil {
+		return Token{}, err
+	}
+
+	request, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
-		signInWithPasswordEndpoint+r.webApiKey,
+		endpoint,
 		bytes.NewReader(jsonReq),
 	)
 	if err != nil {
@@ -60,6 +131,9 @@ func (r *tokenRetriever) GetToken() (Token, error) {
 	}
 
 	request.Header.Add("Content-Type", "application/json")
+	if r.userAgent != "" {
+		request.Header.Set("User-Agent", r.userAgent)
+	}
 
 	resp, err := r.httpClient.Do(request)
 	if err != nil {
@@ -92,6 +166,8 @@ func (r *tokenRetriever) GetToken() (Token, error) {
 		return Token{}, fmt.Errorf("failed to parse expiresIn: %v", err)
 	}
 
+	r.logger.DebugContext(ctx, "signed in with password", slog.Int("expires_in", expiresIn))
+
 	token := Token{
 		ID:           tokenResponse.IDToken,
 		RefreshToken: tokenResponse.RefreshToken,
@@ -105,6 +181,11 @@ func (r *tokenRetriever) GetToken() (Token, error) {
 
 // RefreshToken refreshes an expired token using a refresh token.
 func (r *tokenRetriever) RefreshToken(refreshToken string) (Token, error) {
+	return r.RefreshTokenContext(context.Background(), refreshToken)
+}
+
+// RefreshTokenContext is like RefreshToken but uses ctx for the request.
+func (r *tokenRetriever) RefreshTokenContext(ctx context.Context, refreshToken string) (Token, error) {
 	req := struct {
 		GrantType    string `json:"grant_type"`
 		RefreshToken string `json:"refresh_token"`
@@ -118,9 +199,15 @@ func (r *tokenRetriever) RefreshToken(refreshToken string) (Token, error) {
 		return Token{}, err
 	}
 
-	request, err := http.NewRequest(
+	endpoint, err := r.endpointURL(r.tokenEndpoint)
+	if err != nil {
+		return Token{}, err
+	}
+
+	request, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
-		tokenEndpoint+r.webApiKey,
+		endpoint,
 		bytes.NewReader(jsonReq),
 	)
 	if err != nil {
@@ -128,6 +215,9 @@ func (r *tokenRetriever) RefreshToken(refreshToken string) (Token, error) {
 	}
 
 	request.Header.Add("Content-Type", "application/json")
+	if r.userAgent != "" {
+		request.Header.Set("User-Agent", r.userAgent)
+	}
 
 	resp, err := r.httpClient.Do(request)
 	if err != nil {
@@ -162,6 +252,8 @@ func (r *tokenRetriever) RefreshToken(refreshToken string) (Token, error) {
 		return Token{}, fmt.Errorf("failed to parse expiresIn: %v", err)
 	}
 
+	r.logger.DebugContext(ctx, "refreshed token", slog.Int("expires_in", expiresIn))
+
 	token := Token{
 		ID:           refreshResponse.IDToken,
 		RefreshToken: refreshResponse.RefreshToken,
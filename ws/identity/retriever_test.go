@@ -0,0 +1,138 @@
+package identity_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/zmoog/ws/v2/ws/identity"
+	"github.com/zmoog/ws/v2/ws/identity/identitytest"
+)
+
+func TestManager_SignIn(t *testing.T) {
+	// Arrange
+	server := identitytest.NewServer()
+	defer server.Close()
+	server.AddUser("john.doe@example.com", "secret")
+
+	manager := identity.NewInMemoryManager(server.Config("john.doe@example.com", "secret"))
+
+	// Act
+	token, err := manager.GetToken()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if token.ID == "" || token.RefreshToken == "" {
+		t.Errorf("Expected ID and refresh token, got %+v", token)
+	}
+	if token.IsExpired() {
+		t.Error("Expected a valid token, got an expired one")
+	}
+	if server.SignIns() != 1 {
+		t.Errorf("Expected 1 sign-in, got %d", server.SignIns())
+	}
+}
+
+func TestManager_NilLogger(t *testing.T) {
+	// Arrange
+	server := identitytest.NewServer()
+	defer server.Close()
+	server.AddUser("john.doe@example.com", "secret")
+
+	manager := identity.NewInMemoryManager(server.Config("john.doe@example.com", "secret"), identity.WithLogger(nil))
+
+	// Act
+	_, err := manager.GetToken()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestManager_Refresh(t *testing.T) {
+	// Arrange
+	server := identitytest.NewServer()
+	defer server.Close()
+	server.AddUser("john.doe@example.com", "secret")
+
+	manager := identity.NewInMemoryManager(server.Config("john.doe@example.com", "secret"))
+	first, err := manager.GetToken()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Act
+	refreshed, err := manager.RefreshToken()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if refreshed.ID == first.ID {
+		t.Errorf("Expected a new token, got the same ID %s", refreshed.ID)
+	}
+	if server.Refreshes() != 1 || server.SignIns() != 1 {
+		t.Errorf("Expected 1 sign-in and 1 refresh, got %d and %d", server.SignIns(), server.Refreshes())
+	}
+}
+
+func TestManager_RefreshRevoked(t *testing.T) {
+	// Arrange
+	server := identitytest.NewServer()
+	defer server.Close()
+	server.AddUser("john.doe@example.com", "secret")
+
+	manager := identity.NewInMemoryManager(server.Config("john.doe@example.com", "secret"))
+	if _, err := manager.GetToken(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	server.RevokeRefreshTokens()
+
+	// Act
+	_, err := manager.RefreshToken()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected fallback to sign-in, got %v", err)
+	}
+	if server.SignIns() != 2 {
+		t.Errorf("Expected 2 sign-ins, got %d", server.SignIns())
+	}
+}
+
+func TestManager_SignInErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		fail     string
+		want     string
+	}{
+		{name: "invalid password", password: "wrong", want: "INVALID_PASSWORD"},
+		{name: "server error", password: "secret", fail: "TOO_MANY_ATTEMPTS_TRY_LATER", want: "TOO_MANY_ATTEMPTS_TRY_LATER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := identitytest.NewServer()
+			defer server.Close()
+			server.AddUser("john.doe@example.com", "secret")
+			if tt.fail != "" {
+				server.FailNext(http.StatusTooManyRequests, tt.fail)
+			}
+
+			manager := identity.NewInMemoryManager(server.Config("john.doe@example.com", tt.password))
+
+			// Act
+			_, err := manager.GetToken()
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %s, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Package transport wraps the transport of HTTP clients with the
+// middlewares configured on the ws and identity clients.
+package transport
+
+import "net/http"
+
+// Middleware wraps a transport, e.g. to add headers or trace requests.
+type Middleware = func(http.RoundTripper) http.RoundTripper
+
+// Wrap returns a copy of client with its transport wrapped by the
+// middlewares, or client itself if there are none.
+//
+// Middlewares are applied in order, so the first one is the outermost
+// and sees each request first.
+func Wrap(client *http.Client, middlewares []Middleware) *http.Client {
+	if len(middlewares) == 0 {
+		return client
+	}
+
+	rt := client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		rt = middlewares[i](rt)
+	}
+
+	wrapped := *client
+	wrapped.Transport = rt
+
+	return &wrapped
+}
@@ -1,6 +1,11 @@
 package ws
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
 
 type Devices struct {
 	Devices []Device `json:"devices"`
@@ -14,10 +19,10 @@ type Device struct {
 	RegistrationKey   string     `json:"registrationKey"`
 	FirmwareAvailable string     `json:"firmwareAvailable"`
 	FirmwareInstalled string     `json:"firmwareInstalled"`
-	Type              string     `json:"type"`
+	Type              DeviceType `json:"type"`
 	LastHeartbeat     time.Time  `json:"lastHeartbeat"`
 	LastConfig        LastConfig `json:"lastConfig"`
-	HcMode            string     `json:"hcMode"`
+	HcMode            HcMode     `json:"hcMode"`
 }
 
 type LastConfig struct {
@@ -31,10 +36,10 @@ type Sentio struct {
 	TitlePersonalized         string                     `json:"titlePersonalized"`
 	Rooms                     []Room                     `json:"rooms"`
 	OutdoorTemperatureSensors []OutdoorTemperatureSensor `json:"outdoorTemperatureSensors"`
-	HcMode                    string                     `json:"hcMode"`
-	AutomaticHcMode           string                     `json:"automaticHcMode"`
-	AvailableHcModes          []string                   `json:"availableHcModes"`
-	StandbyMode               string                     `json:"standbyMode"`
+	HcMode                    HcMode                     `json:"hcMode"`
+	AutomaticHcMode           AutomaticHcMode            `json:"automaticHcMode"`
+	AvailableHcModes          []HcMode                   `json:"availableHcModes"`
+	StandbyMode               StandbyMode                `json:"standbyMode"`
 	VacationSettings          VacationSettings           `json:"vacationSettings"`
 	QuietSettings             QuietSettings              `json:"quietSettings"`
 }
@@ -53,55 +58,140 @@ type Room struct {
 	SetpointTemperature     float64                  `json:"setpointTemperature"`
 	MinSetpointTemperature  float64                  `json:"minSetpointTemperature"`
 	MaxSetpointTemperature  float64                  `json:"maxSetpointTemperature"`
-	VacationMode            string                   `json:"vacationMode"`
-	LockMode                string                   `json:"lockMode"`
-	TemperatureState        string                   `json:"temperatureState"`
+	VacationMode            VacationMode             `json:"vacationMode"`
+	LockMode                LockMode                 `json:"lockMode"`
+	TemperatureState        TemperatureState         `json:"temperatureState"`
 	TemperaturePresets      []TemperaturePreset      `json:"temperaturePresets"`
 	SystemModes             []SystemMode             `json:"systemModes"`
 	DehumidificationPresets []DehumidificationPreset `json:"dehumidificationPresets"`
-	DehumidifierState       string                   `json:"dehumidifierState"`
+	DehumidifierState       DehumidifierState        `json:"dehumidifierState"`
 	WeeklySchedule          WeeklySchedule           `json:"weeklySchedule"`
 }
 
 type TemperaturePreset struct {
-	Type                   string  `json:"type"`
-	HcMode                 string  `json:"hcMode"`
-	SetpointTemperature    float64 `json:"setpointTemperature"`
-	MinSetpointTemperature float64 `json:"minSetpointTemperature"`
-	MaxSetpointTemperature float64 `json:"maxSetpointTemperature"`
+	Type                   PresetType `json:"type"`
+	HcMode                 HcMode     `json:"hcMode"`
+	SetpointTemperature    float64    `json:"setpointTemperature"`
+	MinSetpointTemperature float64    `json:"minSetpointTemperature"`
+	MaxSetpointTemperature float64    `json:"maxSetpointTemperature"`
 }
 
 type SystemMode struct {
-	Type                   string  `json:"type"`
-	HcMode                 string  `json:"hcMode"`
-	SetpointTemperature    float64 `json:"setpointTemperature"`
-	MinSetpointTemperature float64 `json:"minSetpointTemperature"`
-	MaxSetpointTemperature float64 `json:"maxSetpointTemperature"`
+	Type                   PresetType `json:"type"`
+	HcMode                 HcMode     `json:"hcMode"`
+	SetpointTemperature    float64    `json:"setpointTemperature"`
+	MinSetpointTemperature float64    `json:"minSetpointTemperature"`
+	MaxSetpointTemperature float64    `json:"maxSetpointTemperature"`
 }
 
 type DehumidificationPreset struct {
-	HcMode              string  `json:"hcMode"`
+	HcMode              HcMode  `json:"hcMode"`
 	Setpoint            float64 `json:"setpoint"`
 	MinHumiditySetpoint float64 `json:"minHumiditySetpoint"`
 	MaxHumiditySetpoint float64 `json:"maxHumiditySetpoint"`
 }
 
 type WeeklySchedule struct {
-	DefaultPresetType string     `json:"defaultPresetType"`
-	ScheduleMode      string     `json:"scheduleMode"`
-	Intervals         []Interval `json:"intervals"`
+	DefaultPresetType PresetType   `json:"defaultPresetType" yaml:"defaultPresetType"`
+	ScheduleMode      ScheduleMode `json:"scheduleMode" yaml:"scheduleMode"`
+	Intervals         []Interval   `json:"intervals" yaml:"intervals"`
 }
 
 type Interval struct {
-	Day              string   `json:"day"`
-	PresetTimeframes []string `json:"presetTimeframes"`
+	Day              Day      `json:"day" yaml:"day"`
+	PresetTimeframes []string `json:"presetTimeframes" yaml:"presetTimeframes"`
 }
 
 type VacationSettings struct {
-	VacationMode      string `json:"vacationMode"`
-	VacationModeUntil string `json:"vacationModeUntil"`
+	VacationMode      VacationMode `json:"vacationMode"`
+	VacationModeUntil time.Time    `json:"vacationModeUntil"`
+}
+
+// vacationSettingsJSON is the wire format of VacationSettings, where the
+// end of the vacation is an RFC 3339 timestamp or an empty string.
+type vacationSettingsJSON struct {
+	VacationMode      VacationMode `json:"vacationMode"`
+	VacationModeUntil string       `json:"vacationModeUntil,omitempty"`
+}
+
+// MarshalJSON omits the end of the vacation when it is not set.
+func (v VacationSettings) MarshalJSON() ([]byte, error) {
+	out := vacationSettingsJSON{VacationMode: v.VacationMode}
+	if !v.VacationModeUntil.IsZero() {
+		out.VacationModeUntil = v.VacationModeUntil.Format(time.RFC3339)
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON accepts an empty or missing end of the vacation,
+// leaving VacationModeUntil at its zero value.
+func (v *VacationSettings) UnmarshalJSON(data []byte) error {
+	var in vacationSettingsJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	var until time.Time
+	if in.VacationModeUntil != "" {
+		var err error
+		until, err = time.Parse(time.RFC3339, in.VacationModeUntil)
+		if err != nil {
+			return fmt.Errorf("invalid vacation end: %w", err)
+		}
+	}
+
+	*v = VacationSettings{VacationMode: in.VacationMode, VacationModeUntil: until}
+
+	return nil
 }
 
 type QuietSettings struct {
-	Mode string `json:"mode"`
+	Mode QuietMode `json:"mode"`
+}
+
+// Room returns the room with the given ID or title.
+//
+// The ID is matched exactly, while the title and the personalized
+// title are matched case-insensitively.
+func (d Device) Room(idOrTitle string) (Room, bool) {
+	rooms := d.LastConfig.Sentio.Rooms
+
+	for _, room := range rooms {
+		if room.ID == idOrTitle {
+			return room, true
+		}
+	}
+
+	for _, room := range rooms {
+		if strings.EqualFold(room.Title, idOrTitle) || strings.EqualFold(room.TitlePersonalized, idOrTitle) {
+			return room, true
+		}
+	}
+
+	return Room{}, false
+}
+
+// DehumidificationPreset returns the dehumidification preset of the room
+// for the given heating/cooling mode.
+func (r Room) DehumidificationPreset(mode HcMode) (DehumidificationPreset, bool) {
+	for _, preset := range r.DehumidificationPresets {
+		if preset.HcMode == mode {
+			return preset, true
+		}
+	}
+
+	return DehumidificationPreset{}, false
+}
+
+// TemperaturePreset returns the temperature preset of the room with the
+// given type and heating/cooling mode.
+func (r Room) TemperaturePreset(presetType PresetType, mode HcMode) (TemperaturePreset, bool) {
+	for _, preset := range r.TemperaturePresets {
+		if preset.Type == presetType && preset.HcMode == mode {
+			return preset, true
+		}
+	}
+
+	return TemperaturePreset{}, false
 }
@@ -0,0 +1,70 @@
+package ws
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVacationSettings_JSON(t *testing.T) {
+	// Arrange
+	var off, on VacationSettings
+
+	// Act
+	errOff := json.Unmarshal([]byte(`{"vacationMode":"VACATION_MODE_OFF","vacationModeUntil":""}`), &off)
+	errOn := json.Unmarshal([]byte(`{"vacationMode":"VACATION_MODE_ON","vacationModeUntil":"2026-12-28T18:00:00Z"}`), &on)
+
+	// Assert
+	if errOff != nil || errOn != nil {
+		t.Fatalf("Expected no error, got %v and %v", errOff, errOn)
+	}
+	if off.VacationMode != VacationModeOff || !off.VacationModeUntil.IsZero() {
+		t.Errorf("Expected vacation mode off without end, got %+v", off)
+	}
+	if want := time.Date(2026, 12, 28, 18, 0, 0, 0, time.UTC); !on.VacationModeUntil.Equal(want) {
+		t.Errorf("Expected vacation end %s, got %s", want, on.VacationModeUntil)
+	}
+
+	data, err := json.Marshal(off)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != `{"vacationMode":"VACATION_MODE_OFF"}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+}
+
+func TestRoom_EnumsJSON(t *testing.T) {
+	// Arrange
+	in := `{"temperatureState":"TEMPERATURE_STATE_BLOCKED","dehumidifierState":"DEHUMIDIFIER_STATE_IDLE","lockMode":"LOCK_MODE_LOCKED"}`
+
+	// Act
+	var room Room
+	err := json.Unmarshal([]byte(in), &room)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if room.TemperatureState != "TEMPERATURE_STATE_BLOCKED" {
+		t.Errorf("Expected the unknown temperature state to be kept, got %q", string(room.TemperatureState))
+	}
+	if got := room.TemperatureState.String(); got != "Blocked" {
+		t.Errorf("Expected label Blocked, got %q", got)
+	}
+	if got := room.DehumidifierState.String(); got != "Idle" {
+		t.Errorf("Expected label Idle, got %q", got)
+	}
+	if got := room.LockMode.String(); got != "Locked" {
+		t.Errorf("Expected label Locked, got %q", got)
+	}
+
+	data, err := json.Marshal(room)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(string(data), `"temperatureState":"TEMPERATURE_STATE_BLOCKED"`) {
+		t.Errorf("Expected the unknown temperature state in %s", data)
+	}
+}
@@ -0,0 +1,92 @@
+package ws
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the default number of times an idempotent
+	// request is retried after a transient failure.
+	DefaultMaxRetries = 2
+
+	// DefaultMinBackoff is the default delay before the first retry.
+	DefaultMinBackoff = 200 * time.Millisecond
+
+	// DefaultMaxBackoff is the default upper bound of the delay
+	// between retries.
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithMaxRetries sets how many times an idempotent request is retried
+// after a timeout, a dropped or refused connection, or a 429 or 5xx
+// response. Zero disables retries.
+func WithMaxRetries(retries int) Option {
+	return func(c *Client) {
+		c.maxRetries = max(retries, 0)
+	}
+}
+
+// WithBackoff sets the delay before the first retry and the upper bound
+// of the delay between retries. The delay doubles after each attempt
+// and is randomized to spread out retries from concurrent clients.
+func WithBackoff(minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send the requests,
+// for example to configure proxies, TLS settings or timeouts.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithLogger sets the logger used to report requests and retries.
+// A nil logger discards the records.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if logger == nil {
+			logger = discardLogger()
+		}
+		c.logger = logger
+	}
+}
+
+// WithBaseURL overrides the BlazeDeviceService endpoint,
+// for example to send the requests to a test server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.endpoint = baseURL
+	}
+}
+
+// WithTransportMiddleware wraps the transport of the HTTP client.
+//
+// Middlewares are applied in order, so the first one added is the
+// outermost and sees each request first.
+func WithTransportMiddleware(middleware func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middleware)
+	}
+}
+
+// discardLogger returns a logger that drops all records.
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
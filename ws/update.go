@@ -0,0 +1,54 @@
+package ws
+
+// ConfigUpdate is a partial update of a device configuration.
+//
+// Fields left at their zero value are not sent to the device and
+// keep their current value.
+type ConfigUpdate struct {
+	Sentio SentioUpdate `json:"sentio"`
+}
+
+// SentioUpdate is a partial update of the Sentio configuration.
+type SentioUpdate struct {
+	Rooms            []RoomUpdate      `json:"rooms,omitempty"`
+	HcMode           HcMode            `json:"hcMode,omitempty"`
+	StandbyMode      StandbyMode       `json:"standbyMode,omitempty"`
+	VacationSettings *VacationSettings `json:"vacationSettings,omitempty"`
+	QuietSettings    *QuietSettings    `json:"quietSettings,omitempty"`
+}
+
+// RoomUpdate is a partial update of a room, identified by its ID.
+type RoomUpdate struct {
+	ID                  string   `json:"id"`
+	SetpointTemperature *float64 `json:"setpointTemperature,omitempty"`
+	LockMode            LockMode `json:"lockMode,omitempty"`
+
+	// PresetType activates a temperature preset, setting the room
+	// setpoint to the preset one for the current heating/cooling mode.
+	PresetType PresetType `json:"presetType,omitempty"`
+
+	TemperaturePresets      []TemperaturePresetUpdate      `json:"temperaturePresets,omitempty"`
+	DehumidificationPresets []DehumidificationPresetUpdate `json:"dehumidificationPresets,omitempty"`
+}
+
+// TemperaturePresetUpdate changes the setpoint of the temperature
+// preset with a type for a heating/cooling mode.
+type TemperaturePresetUpdate struct {
+	Type                PresetType `json:"type"`
+	HcMode              HcMode     `json:"hcMode"`
+	SetpointTemperature float64    `json:"setpointTemperature"`
+}
+
+// DehumidificationPresetUpdate changes the humidity setpoint
+// of the dehumidification preset for a heating/cooling mode.
+type DehumidificationPresetUpdate struct {
+	HcMode   HcMode  `json:"hcMode"`
+	Setpoint float64 `json:"setpoint"`
+}
+
+// UpdatedRoom is a room after an update, with the heating/cooling mode
+// of the updated device, which selects the active presets of the room.
+type UpdatedRoom struct {
+	Room
+	HcMode HcMode
+}
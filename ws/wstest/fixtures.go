@@ -0,0 +1,102 @@
+package wstest
+
+import (
+	"time"
+
+	"github.com/zmoog/ws/v2/ws"
+)
+
+// NewDevice returns a Sentio device with realistic defaults
+// and the given rooms.
+func NewDevice(name string, rooms ...ws.Room) ws.Device {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	return ws.Device{
+		Name:              name,
+		CreateTime:        now.Add(-365 * 24 * time.Hour),
+		UpdateTime:        now,
+		SerialNumber:      "98765432109876",
+		RegistrationKey:   "A1B2C-D3E4F-5G6H",
+		FirmwareAvailable: "17.2.1",
+		FirmwareInstalled: "17.2.1",
+		Type:              ws.DeviceTypeSentioCCU,
+		LastHeartbeat:     now,
+		HcMode:            ws.HcModeHeating,
+		LastConfig: ws.LastConfig{
+			Name:      name + "/config",
+			Timestamp: now,
+			Sentio: ws.Sentio{
+				Title:            "Sentio",
+				Rooms:            rooms,
+				HcMode:           ws.HcModeHeating,
+				AutomaticHcMode:  ws.AutomaticHcModeOff,
+				AvailableHcModes: []ws.HcMode{ws.HcModeHeating, ws.HcModeCooling},
+				StandbyMode:      ws.StandbyModeOff,
+				OutdoorTemperatureSensors: []ws.OutdoorTemperatureSensor{
+					{ID: "outdoor-1", OutdoorTemperature: 8.5},
+				},
+				VacationSettings: ws.VacationSettings{
+					VacationMode: ws.VacationModeOff,
+				},
+				QuietSettings: ws.QuietSettings{
+					Mode: ws.QuietModeOff,
+				},
+			},
+		},
+	}
+}
+
+// NewRoom returns a room with realistic defaults.
+func NewRoom(id, title string) ws.Room {
+	return ws.Room{
+		ID:                     id,
+		Title:                  title,
+		AirTemperature:         20.5,
+		Humidity:               45,
+		SetpointTemperature:    21,
+		MinSetpointTemperature: 6,
+		MaxSetpointTemperature: 30,
+		VacationMode:           ws.VacationModeOff,
+		LockMode:               ws.LockModeUnlocked,
+		TemperatureState:       ws.TemperatureStateIdle,
+		DehumidifierState:      ws.DehumidifierStateIdle,
+		TemperaturePresets: []ws.TemperaturePreset{
+			{Type: ws.PresetTypeEco, HcMode: ws.HcModeHeating, SetpointTemperature: 18, MinSetpointTemperature: 6, MaxSetpointTemperature: 30},
+			{Type: ws.PresetTypeComfort, HcMode: ws.HcModeHeating, SetpointTemperature: 21, MinSetpointTemperature: 6, MaxSetpointTemperature: 30},
+			{Type: ws.PresetTypeExtraComfort, HcMode: ws.HcModeHeating, SetpointTemperature: 23, MinSetpointTemperature: 6, MaxSetpointTemperature: 30},
+			{Type: ws.PresetTypeEco, HcMode: ws.HcModeCooling, SetpointTemperature: 27, MinSetpointTemperature: 18, MaxSetpointTemperature: 35},
+			{Type: ws.PresetTypeComfort, HcMode: ws.HcModeCooling, SetpointTemperature: 24, MinSetpointTemperature: 18, MaxSetpointTemperature: 35},
+			{Type: ws.PresetTypeExtraComfort, HcMode: ws.HcModeCooling, SetpointTemperature: 22, MinSetpointTemperature: 18, MaxSetpointTemperature: 35},
+		},
+		DehumidificationPresets: []ws.DehumidificationPreset{
+			{HcMode: ws.HcModeCooling, Setpoint: 60, MinHumiditySetpoint: 30, MaxHumiditySetpoint: 80},
+		},
+		WeeklySchedule: NewWeeklySchedule(),
+	}
+}
+
+// NewWeeklySchedule returns a weekly schedule using the comfort preset
+// in the morning and evening on weekdays and during the day on weekends.
+func NewWeeklySchedule() ws.WeeklySchedule {
+	schedule := ws.WeeklySchedule{
+		DefaultPresetType: ws.PresetTypeEco,
+		ScheduleMode:      ws.ScheduleModeOn,
+	}
+
+	// The preset timeframes are opaque to the client: these values are
+	// placeholders, not a capture of the encoding used by the devices.
+	days := []ws.Day{"DAY_MONDAY", "DAY_TUESDAY", "DAY_WEDNESDAY", "DAY_THURSDAY", "DAY_FRIDAY", "DAY_SATURDAY", "DAY_SUNDAY"}
+	for i, day := range days {
+		timeframes := []string{"06:00-08:00@PRESET_TYPE_COMFORT", "17:00-22:00@PRESET_TYPE_COMFORT"}
+		if i >= 5 {
+			timeframes = []string{"08:00-23:00@PRESET_TYPE_COMFORT"}
+		}
+
+		schedule.Intervals = append(schedule.Intervals, ws.Interval{
+			Day:              day,
+			PresetTimeframes: timeframes,
+		})
+	}
+
+	return schedule
+}
@@ -0,0 +1,446 @@
+// Package wstest provides an in-process fake BlazeDeviceService
+// for testing code that uses the ws package.
+package wstest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zmoog/ws/v2/ws"
+	"github.com/zmoog/ws/v2/ws/identity"
+)
+
+// Token is the ID token returned by StaticIdentity.
+const Token = "wstest-token"
+
+// Server is a fake BlazeDeviceService holding an in-memory set of devices.
+//
+// It implements the methods used by ws.Client with the same JSON
+// encoding as the real service, and can be configured to add latency
+// and to fail requests with a given error code.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	devices  []ws.Device
+	latency  time.Duration
+	failures map[string][]*ws.APIError
+	requests map[string]int
+	now      func() time.Time
+}
+
+// NewServer starts a fake BlazeDeviceService serving the given devices.
+// The caller should call Close when finished, to shut it down.
+func NewServer(devices ...ws.Device) *Server {
+	s := &Server{
+		devices:  slices.Clone(devices),
+		failures: make(map[string][]*ws.APIError),
+		requests: make(map[string]int),
+		now:      time.Now,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /ListDevices", s.handle("ListDevices", s.listDevices))
+	mux.HandleFunc("POST /GetDevice", s.handle("GetDevice", s.getDevice))
+	mux.HandleFunc("POST /UpdateConfig", s.handle("UpdateConfig", s.updateConfig))
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Client returns a ws.Client sending requests to the server and
+// authenticating with a static token.
+func (s *Server) Client(opts ...ws.Option) *ws.Client {
+	opts = append([]ws.Option{ws.WithHTTPClient(s.Server.Client())}, opts...)
+	return ws.NewClient(StaticIdentity{}, s.URL, opts...)
+}
+
+// AddDevice adds a device to the server, replacing any device
+// with the same name.
+func (s *Server) AddDevice(device ws.Device) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.devices {
+		if s.devices[i].Name == device.Name {
+			s.devices[i] = device
+			return
+		}
+	}
+	s.devices = append(s.devices, device)
+}
+
+// Device returns the current state of a device.
+func (s *Server) Device(name string) (ws.Device, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	device := s.device(name)
+	if device == nil {
+		return ws.Device{}, false
+	}
+
+	return cloneDevice(*device), true
+}
+
+// SetLatency delays every response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = d
+}
+
+// FailNext makes the next request to method fail with the given code
+// and message. Calling it multiple times queues multiple failures.
+func (s *Server) FailNext(method string, code ws.Code, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[method] = append(s.failures[method], &ws.APIError{
+		StatusCode: StatusCode(code),
+		Code:       code,
+		Message:    message,
+	})
+}
+
+// Requests returns the number of requests received for method,
+// including the failed ones.
+func (s *Server) Requests(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[method]
+}
+
+// handle wraps a method implementation with the authentication check,
+// latency and failure injection, and the JSON encoding.
+func (s *Server) handle(method string, fn func(body json.RawMessage) (any, *ws.APIError)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests[method]++
+		latency := s.latency
+		var apiErr *ws.APIError
+		if queue := s.failures[method]; len(queue) > 0 {
+			apiErr, s.failures[method] = queue[0], queue[1:]
+		}
+		s.mu.Unlock()
+
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+
+		// Any token is accepted, so the server can be used with
+		// both StaticIdentity and a fake identity server.
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); !ok || token == "" {
+			writeError(w, &ws.APIError{Code: ws.CodeUnauthenticated, Message: "missing token"})
+			return
+		}
+
+		var body json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, &ws.APIError{Code: ws.CodeInvalidArgument, Message: err.Error()})
+			return
+		}
+
+		// The response is encoded under the lock, since it may share
+		// the rooms and schedules with the devices of the server.
+		s.mu.Lock()
+		resp, apiErr := fn(body)
+		var data []byte
+		if apiErr == nil {
+			var err error
+			if data, err = json.Marshal(resp); err != nil {
+				apiErr = &ws.APIError{Code: ws.CodeInternal, Message: err.Error()}
+			}
+		}
+		s.mu.Unlock()
+
+		if apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(append(data, '\n'))
+	}
+}
+
+func (s *Server) listDevices(json.RawMessage) (any, *ws.APIError) {
+	return ws.Devices{Devices: slices.Clone(s.devices)}, nil
+}
+
+func (s *Server) getDevice(body json.RawMessage) (any, *ws.APIError) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, &ws.APIError{Code: ws.CodeInvalidArgument, Message: err.Error()}
+	}
+
+	device := s.device(req.Name)
+	if device == nil {
+		return nil, notFound("device", req.Name)
+	}
+
+	return device, nil
+}
+
+func (s *Server) updateConfig(body json.RawMessage) (any, *ws.APIError) {
+	var req struct {
+		Name   string          `json:"name"`
+		Config ws.ConfigUpdate `json:"config"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, &ws.APIError{Code: ws.CodeInvalidArgument, Message: err.Error()}
+	}
+
+	device := s.device(req.Name)
+	if device == nil {
+		return nil, notFound("device", req.Name)
+	}
+
+	// Apply the update to a copy, so a failed update
+	// leaves the device unchanged.
+	updated := cloneDevice(*device)
+	now := s.now().UTC()
+	if apiErr := applyUpdate(&updated, req.Config, now); apiErr != nil {
+		return nil, apiErr
+	}
+
+	updated.UpdateTime = now
+	updated.LastConfig.Timestamp = now
+	*device = updated
+
+	return updated, nil
+}
+
+// device returns a pointer to the device with the given name, or nil.
+// The caller must hold the lock.
+func (s *Server) device(name string) *ws.Device {
+	for i := range s.devices {
+		if s.devices[i].Name == name {
+			return &s.devices[i]
+		}
+	}
+	return nil
+}
+
+// applyUpdate applies a partial configuration update to a device.
+func applyUpdate(device *ws.Device, update ws.ConfigUpdate, now time.Time) *ws.APIError {
+	sentio := &device.LastConfig.Sentio
+
+	if mode := update.Sentio.HcMode; mode != "" {
+		if !slices.Contains(sentio.AvailableHcModes, mode) {
+			return invalidArgument("heating/cooling mode %q not available", string(mode))
+		}
+		sentio.HcMode = mode
+		device.HcMode = mode
+	}
+
+	switch mode := update.Sentio.StandbyMode; mode {
+	case "":
+	case ws.StandbyModeOn, ws.StandbyModeOff:
+		sentio.StandbyMode = mode
+	default:
+		return invalidArgument("unknown standby mode %q", string(mode))
+	}
+
+	if quiet := update.Sentio.QuietSettings; quiet != nil {
+		if !slices.Contains(ws.QuietModes, quiet.Mode) {
+			return invalidArgument("unknown quiet mode %q", string(quiet.Mode))
+		}
+		sentio.QuietSettings = *quiet
+	}
+
+	if vacation := update.Sentio.VacationSettings; vacation != nil {
+		switch vacation.VacationMode {
+		case ws.VacationModeOn:
+			if !vacation.VacationModeUntil.After(now) {
+				return invalidArgument("vacation end %s is not in the future", vacation.VacationModeUntil.Format(time.RFC3339))
+			}
+			sentio.VacationSettings = *vacation
+		case ws.VacationModeOff:
+			sentio.VacationSettings = ws.VacationSettings{VacationMode: ws.VacationModeOff}
+		default:
+			return invalidArgument("unknown vacation mode %q", string(vacation.VacationMode))
+		}
+	}
+
+	for _, roomUpdate := range update.Sentio.Rooms {
+		room := findRoom(device, roomUpdate.ID)
+		if room == nil {
+			return notFound("room", roomUpdate.ID)
+		}
+
+		if t := roomUpdate.SetpointTemperature; t != nil {
+			if *t < room.MinSetpointTemperature || *t > room.MaxSetpointTemperature {
+				return invalidArgument("setpoint temperature %.1f out of range [%.1f, %.1f]",
+					*t, room.MinSetpointTemperature, room.MaxSetpointTemperature)
+			}
+			room.SetpointTemperature = *t
+		}
+
+		for _, presetUpdate := range roomUpdate.TemperaturePresets {
+			preset := findTemperaturePreset(room, presetUpdate.Type, presetUpdate.HcMode)
+			if preset == nil {
+				return notFound("temperature preset", fmt.Sprintf("%s/%s", string(presetUpdate.Type), string(presetUpdate.HcMode)))
+			}
+			t := presetUpdate.SetpointTemperature
+			if t < preset.MinSetpointTemperature || t > preset.MaxSetpointTemperature {
+				return invalidArgument("preset temperature %.1f out of range [%.1f, %.1f]",
+					t, preset.MinSetpointTemperature, preset.MaxSetpointTemperature)
+			}
+			preset.SetpointTemperature = t
+		}
+
+		if presetType := roomUpdate.PresetType; presetType != "" {
+			preset := findTemperaturePreset(room, presetType, sentio.HcMode)
+			if preset == nil {
+				return notFound("temperature preset", fmt.Sprintf("%s/%s", string(presetType), string(sentio.HcMode)))
+			}
+			room.SetpointTemperature = preset.SetpointTemperature
+		}
+
+		for _, presetUpdate := range roomUpdate.DehumidificationPresets {
+			preset := findDehumidificationPreset(room, presetUpdate.HcMode)
+			if preset == nil {
+				return notFound("dehumidification preset", string(presetUpdate.HcMode))
+			}
+			if presetUpdate.Setpoint < preset.MinHumiditySetpoint || presetUpdate.Setpoint > preset.MaxHumiditySetpoint {
+				return invalidArgument("humidity setpoint %.1f out of range [%.1f, %.1f]",
+					presetUpdate.Setpoint, preset.MinHumiditySetpoint, preset.MaxHumiditySetpoint)
+			}
+			preset.Setpoint = presetUpdate.Setpoint
+		}
+
+		switch mode := roomUpdate.LockMode; mode {
+		case "":
+		case ws.LockModeLocked, ws.LockModeUnlocked:
+			room.LockMode = mode
+		default:
+			return invalidArgument("unknown lock mode %q", string(mode))
+		}
+	}
+
+	return nil
+}
+
+func findRoom(device *ws.Device, id string) *ws.Room {
+	rooms := device.LastConfig.Sentio.Rooms
+	for i := range rooms {
+		if rooms[i].ID == id {
+			return &rooms[i]
+		}
+	}
+	return nil
+}
+
+func findTemperaturePreset(room *ws.Room, presetType ws.PresetType, mode ws.HcMode) *ws.TemperaturePreset {
+	for i := range room.TemperaturePresets {
+		if room.TemperaturePresets[i].Type == presetType && room.TemperaturePresets[i].HcMode == mode {
+			return &room.TemperaturePresets[i]
+		}
+	}
+	return nil
+}
+
+func findDehumidificationPreset(room *ws.Room, mode ws.HcMode) *ws.DehumidificationPreset {
+	for i := range room.DehumidificationPresets {
+		if room.DehumidificationPresets[i].HcMode == mode {
+			return &room.DehumidificationPresets[i]
+		}
+	}
+	return nil
+}
+
+// cloneDevice returns a copy of the device that does not share
+// the rooms and their presets with the original.
+func cloneDevice(device ws.Device) ws.Device {
+	rooms := slices.Clone(device.LastConfig.Sentio.Rooms)
+	for i := range rooms {
+		rooms[i].TemperaturePresets = slices.Clone(rooms[i].TemperaturePresets)
+		rooms[i].DehumidificationPresets = slices.Clone(rooms[i].DehumidificationPresets)
+	}
+	device.LastConfig.Sentio.Rooms = rooms
+	return device
+}
+
+func notFound(kind, name string) *ws.APIError {
+	return &ws.APIError{Code: ws.CodeNotFound, Message: fmt.Sprintf("%s %q not found", kind, name)}
+}
+
+func invalidArgument(format string, args ...any) *ws.APIError {
+	return &ws.APIError{Code: ws.CodeInvalidArgument, Message: fmt.Sprintf(format, args...)}
+}
+
+// writeError writes an error using the Connect error format.
+func writeError(w http.ResponseWriter, apiErr *ws.APIError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(StatusCode(apiErr.Code))
+	_ = json.NewEncoder(w).Encode(apiErr)
+}
+
+// StatusCode returns the HTTP status code the Connect protocol
+// uses for an error code.
+func StatusCode(code ws.Code) int {
+	switch code {
+	case ws.CodeCanceled:
+		return 499
+	case ws.CodeInvalidArgument, ws.CodeFailedPrecondition, ws.CodeOutOfRange:
+		return http.StatusBadRequest
+	case ws.CodeDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case ws.CodeNotFound:
+		return http.StatusNotFound
+	case ws.CodeAlreadyExists, ws.CodeAborted:
+		return http.StatusConflict
+	case ws.CodePermissionDenied:
+		return http.StatusForbidden
+	case ws.CodeResourceExhausted:
+		return http.StatusTooManyRequests
+	case ws.CodeUnimplemented:
+		return http.StatusNotImplemented
+	case ws.CodeUnavailable:
+		return http.StatusServiceUnavailable
+	case ws.CodeUnauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// StaticIdentity is an identity.Manager always returning Token.
+type StaticIdentity struct{}
+
+func (StaticIdentity) GetToken() (identity.Token, error) {
+	return StaticIdentity{}.GetTokenContext(context.Background())
+}
+
+func (StaticIdentity) GetTokenContext(ctx context.Context) (identity.Token, error) {
+	return identity.Token{ID: Token, ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+func (StaticIdentity) RefreshToken() (identity.Token, error) {
+	return StaticIdentity{}.GetTokenContext(context.Background())
+}
+
+func (StaticIdentity) RefreshTokenContext(ctx context.Context) (identity.Token, error) {
+	return StaticIdentity{}.GetTokenContext(ctx)
+}
@@ -0,0 +1,137 @@
+package wstest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zmoog/ws/v2/ws"
+	"github.com/zmoog/ws/v2/ws/wstest"
+)
+
+func TestServer_ListAndGetDevice(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(
+		wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")),
+	)
+	defer server.Close()
+
+	client := server.Client()
+
+	// Act
+	devices, err := client.ListDevices()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	device, err := client.GetDevice("devices/home")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(devices) != 1 || devices[0].Name != "devices/home" {
+		t.Errorf("Expected devices/home, got %+v", devices)
+	}
+	if _, ok := device.Room("Kitchen"); !ok {
+		t.Error("Expected the Kitchen room")
+	}
+
+	_, err = client.GetDevice("devices/missing")
+	if !ws.IsNotFound(err) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestServer_SetRoomTemperature(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(
+		wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen")),
+	)
+	defer server.Close()
+
+	// Act
+	room, err := server.Client().SetRoomTemperature("devices/home", "room-1", 22.5)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if room.SetpointTemperature != 22.5 {
+		t.Errorf("Expected setpoint 22.5, got %.1f", room.SetpointTemperature)
+	}
+	device, _ := server.Device("devices/home")
+	if stored, _ := device.Room("room-1"); stored.SetpointTemperature != 22.5 {
+		t.Errorf("Expected stored setpoint 22.5, got %.1f", stored.SetpointTemperature)
+	}
+}
+
+func TestServer_FailNext(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(wstest.NewDevice("devices/home"))
+	defer server.Close()
+
+	server.FailNext("GetDevice", ws.CodeUnavailable, "maintenance")
+	server.FailNext("GetDevice", ws.CodePermissionDenied, "forbidden")
+
+	client := server.Client(ws.WithBackoff(time.Millisecond, time.Millisecond))
+
+	// Act
+	_, err := client.GetDevice("devices/home")
+
+	// Assert
+	if !ws.IsPermissionDenied(err) {
+		t.Errorf("Expected permission denied error, got %v", err)
+	}
+	if got := server.Requests("GetDevice"); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+}
+
+func TestServer_Latency(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(wstest.NewDevice("devices/home"))
+	defer server.Close()
+	server.SetLatency(200 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act
+	_, err := server.Client().ListDevicesContext(ctx)
+
+	// Assert
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestServer_ConcurrentRequests(t *testing.T) {
+	// Arrange
+	server := wstest.NewServer(
+		wstest.NewDevice("devices/home", wstest.NewRoom("room-1", "Kitchen"), wstest.NewRoom("room-2", "Bedroom")),
+	)
+	defer server.Close()
+
+	client := server.Client()
+	errs := make(chan error, 20)
+
+	// Act
+	for i := 0; i < 10; i++ {
+		go func() {
+			_, err := client.GetDevice("devices/home")
+			errs <- err
+		}()
+		go func(i int) {
+			_, err := client.SetRoomTemperature("devices/home", "room-1", 18+float64(i)/2)
+			errs <- err
+		}(i)
+	}
+
+	// Assert
+	for i := 0; i < 20; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	}
+}